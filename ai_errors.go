@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// AI 请求错误分类：决定是否重试、是否切换服务商，以及提示用户如何处理
+// ============================================
+
+// AIErrorKind AI 请求错误类型
+type AIErrorKind string
+
+const (
+	AIErrAuth           AIErrorKind = "auth"             // API Key 无效或无权限
+	AIErrQuota          AIErrorKind = "quota"            // 余额或额度用尽
+	AIErrRateLimited    AIErrorKind = "rate_limited"     // 请求过于频繁
+	AIErrBadRequest     AIErrorKind = "bad_request"      // 请求参数或模型名有误
+	AIErrContextTooLong AIErrorKind = "context_too_long" // 超出模型上下文长度
+	AIErrServer         AIErrorKind = "server"           // 服务端错误或过载
+	AIErrNetwork        AIErrorKind = "network"          // 网络错误或超时
+	AIErrUnknown        AIErrorKind = "unknown"
+)
+
+const (
+	retryBaseDelay     = time.Second      // 第一次重试前的等待时间，之后每次翻倍
+	retryMaxDelay      = 30 * time.Second // 指数退避的上限
+	maxRetryAfterDelay = 2 * time.Minute  // 服务商要求的等待时间超过该值时按该值等待
+)
+
+// AIError 分类后的 AI 请求错误，Err 为原始错误
+type AIError struct {
+	Kind       AIErrorKind
+	StatusCode int
+	RetryAfter time.Duration // 限流时服务商通过 Retry-After / x-ratelimit-reset 要求的等待时间
+	Err        error
+}
+
+func (e *AIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AIError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable 只有限流、服务端错误与网络错误值得重试，其余错误重试也不会成功
+func (e *AIError) Retryable() bool {
+	switch e.Kind {
+	case AIErrRateLimited, AIErrServer, AIErrNetwork:
+		return true
+	}
+	return false
+}
+
+// Hint 面向用户的处理建议
+func (e *AIError) Hint() string {
+	switch e.Kind {
+	case AIErrAuth:
+		return "API Key 无效、已过期或权限不足，请在设置中检查 API Key"
+	case AIErrQuota:
+		return "账户余额或额度已用尽，请充值或切换到其他服务商"
+	case AIErrRateLimited:
+		return "请求过于频繁，请降低并发数或每分钟请求数后重试"
+	case AIErrBadRequest:
+		return "请求被拒绝，请检查接口地址与模型名称是否正确"
+	case AIErrContextTooLong:
+		return "简历内容超出模型上下文长度，请换用上下文更长的模型"
+	case AIErrServer:
+		return "AI 服务暂时不可用，请稍后重试或配置备用服务商"
+	case AIErrNetwork:
+		return "网络连接失败或超时，请检查网络、代理与接口地址"
+	}
+	return ""
+}
+
+// classifyAIError 把适配器返回的错误归类；已分类的错误原样返回
+func classifyAIError(err error) *AIError {
+	var ae *AIError
+	if errors.As(err, &ae) {
+		return ae
+	}
+	ae = &AIError{Kind: AIErrUnknown, Err: err}
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		ae.StatusCode = apiErr.StatusCode
+		msg := strings.ToLower(apiErr.Message)
+		switch code := apiErr.StatusCode; {
+		case code == 401 || code == 403:
+			ae.Kind = AIErrAuth
+		case code == 402 || isQuotaMessage(msg):
+			ae.Kind = AIErrQuota
+		case code == 429:
+			ae.Kind = AIErrRateLimited
+		case code == 413 || isContextLengthMessage(msg):
+			ae.Kind = AIErrContextTooLong
+		case code == 408:
+			ae.Kind = AIErrNetwork
+		case code >= 500:
+			ae.Kind = AIErrServer
+		case code >= 400:
+			ae.Kind = AIErrBadRequest
+		}
+		// x-ratelimit-reset 等响应头每次都会返回，只有被限流时才按其等待
+		if ae.Kind == AIErrRateLimited {
+			ae.RetryAfter = apiErr.RetryAfter
+		}
+		return ae
+	}
+
+	var timeout interface{ Timeout() bool }
+	var netErr net.Error
+	switch {
+	case errors.As(err, &timeout) && timeout.Timeout(),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		ae.Kind = AIErrNetwork
+	}
+	return ae
+}
+
+// isQuotaMessage 额度用尽的提示；部分服务商用 429 表示额度用尽，需要按内容与限流区分
+func isQuotaMessage(msg string) bool {
+	for _, kw := range []string{"quota", "insufficient", "billing", "credit", "余额", "额度", "欠费"} {
+		if strings.Contains(msg, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// isContextLengthMessage 超出上下文长度的提示（各家通常返回 400）
+func isContextLengthMessage(msg string) bool {
+	for _, kw := range []string{"context_length", "context length", "context window", "maximum context", "too many tokens", "prompt is too long", "上下文长度", "超出最大长度"} {
+		if strings.Contains(msg, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfterHeader 解析服务商要求的等待时间：Retry-After（秒数或 HTTP 日期）、
+// x-ratelimit-reset*（OpenAI 为 "6m0s" 形式，其他服务商为秒数或 Unix 时间戳）、
+// anthropic-ratelimit-*-reset（RFC 3339 时间）
+func retryAfterHeader(h http.Header) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(secs * float64(time.Second))
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t)
+		}
+	}
+	var wait time.Duration
+	for key, values := range h {
+		key = strings.ToLower(key)
+		if len(values) == 0 || !strings.Contains(key, "ratelimit") || !strings.HasSuffix(key, "reset") &&
+			!strings.HasSuffix(key, "reset-requests") && !strings.HasSuffix(key, "reset-tokens") {
+			continue
+		}
+		wait = max(wait, parseResetValue(values[0]))
+	}
+	return wait
+}
+
+// parseResetValue 解析单个 reset 头的值
+func parseResetValue(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if d, err := time.ParseDuration(v); err == nil {
+		return d
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return time.Until(t)
+	}
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		// 大于一天的数值按 Unix 时间戳处理
+		if n > 86400 {
+			return time.Until(time.Unix(int64(n), 0))
+		}
+		return time.Duration(n * float64(time.Second))
+	}
+	return 0
+}
+
+// retryDelay 第 attempt 次重试（从 1 开始）前的等待时间：指数退避加随机抖动，
+// 服务商指定了等待时间时以其为准
+func retryDelay(attempt int, ae *AIError) time.Duration {
+	if ae != nil && ae.RetryAfter > 0 {
+		wait := ae.RetryAfter
+		if wait > maxRetryAfterDelay {
+			wait = maxRetryAfterDelay
+		}
+		return wait + rand.N(500*time.Millisecond)
+	}
+	d := retryMaxDelay
+	if attempt < 10 {
+		if exp := retryBaseDelay << (attempt - 1); exp < d {
+			d = exp
+		}
+	}
+	// 抖动取 [d/2, d)，避免并发任务同时重试
+	return d/2 + rand.N(d/2)
+}
+
+// analysisErrorEvent analysis:error 事件内容；AI 请求失败时附带错误类型与处理建议
+func analysisErrorEvent(resumeID, msg string, err error) map[string]interface{} {
+	ev := map[string]interface{}{
+		"id":    resumeID,
+		"error": msg,
+	}
+	var ae *AIError
+	if errors.As(err, &ae) {
+		ev["errorType"] = ae.Kind
+		ev["hint"] = ae.Hint()
+		ev["retryable"] = ae.Retryable()
+		if ae.StatusCode != 0 {
+			ev["statusCode"] = ae.StatusCode
+		}
+	}
+	return ev
+}
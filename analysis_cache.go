@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ============================================
+// 分析结果缓存：简历内容、岗位配置、提示词与模型都没有变化时直接返回上次的结果，不再调用 AI
+// ============================================
+
+// analysisPromptVersion 提示词版本；修改提示词模板、输出 schema 或结果后处理时递增，使旧缓存失效
+const analysisPromptVersion = 1
+
+// analysisTemperature 分析请求的 temperature
+const analysisTemperature = 0.2
+
+// analysisCacheKey 缓存键：以下内容任一变化都需要重新分析
+type analysisCacheKey struct {
+	FileName      string     `json:"file_name"` // 提示词中包含文件名
+	Content       string     `json:"content"`   // 空白归一化后的简历内容
+	Job           *JobConfig `json:"job"`
+	PromptVersion int        `json:"prompt_version"`
+	Model         string     `json:"model"`
+	Temperature   float64    `json:"temperature"`
+}
+
+// analysisCacheHash 缓存键的 SHA-256
+func analysisCacheHash(fileName, content string, jobCfg *JobConfig, model string) string {
+	data, _ := json.Marshal(analysisCacheKey{
+		FileName:      fileName,
+		Content:       strings.Join(strings.Fields(content), " "),
+		Job:           jobCfg,
+		PromptVersion: analysisPromptVersion,
+		Model:         strings.TrimSpace(model),
+		Temperature:   analysisTemperature,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// analysisCacheDir 缓存目录
+func (a *App) analysisCacheDir() string {
+	dir := filepath.Join(a.getDataDir(), "analysis_cache")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// loadCachedAnalysis 读取缓存的分析结果，未命中时返回 nil
+func (a *App) loadCachedAnalysis(key string) *AnalysisResult {
+	data, err := os.ReadFile(filepath.Join(a.analysisCacheDir(), key+".json"))
+	if err != nil {
+		return nil
+	}
+	var result AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		log.Printf("[loadCachedAnalysis] 缓存损坏，忽略: %s, %v", key, err)
+		return nil
+	}
+	return &result
+}
+
+// saveCachedAnalysis 保存分析结果；用量只属于产生它的那次分析，不写入缓存
+func (a *App) saveCachedAnalysis(key string, result *AnalysisResult) {
+	cached := *result
+	cached.Usage = nil
+	cached.Cached = false
+	data, _ := json.MarshalIndent(&cached, "", "  ")
+	if err := os.WriteFile(filepath.Join(a.analysisCacheDir(), key+".json"), data, 0644); err != nil {
+		log.Printf("[saveCachedAnalysis] 保存缓存失败: %v", err)
+	}
+}
+
+// ClearAnalysisCache 清空分析结果缓存
+func (a *App) ClearAnalysisCache() error {
+	dir := filepath.Join(a.getDataDir(), "analysis_cache")
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	log.Printf("[ClearAnalysisCache] 已清空分析缓存")
+	return nil
+}
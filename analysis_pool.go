@@ -0,0 +1,111 @@
+package main
+
+import (
+	"log"
+	"sync"
+)
+
+// ============================================
+// 批量分析：固定数量的 worker 并发分析，请求速率由 ratelimit.go 控制
+// ============================================
+
+// defaultAnalysisConcurrency AIConfig.Concurrency 未设置时的并发数
+const defaultAnalysisConcurrency = 3
+
+// maxAnalysisConcurrency 并发数上限
+const maxAnalysisConcurrency = 16
+
+// batchProgress 批量分析进度；Current 为已结束（成功或失败）的数量，并发时按完成顺序递增
+type batchProgress struct {
+	JobID    string
+	ResumeID string
+	Status   string // started / done / error
+	Current  int
+	Failed   int
+	Total    int
+}
+
+// analysisConcurrency 批量分析并发数
+func analysisConcurrency(cfg *AIConfig) int {
+	n := cfg.Concurrency
+	if n <= 0 {
+		n = defaultAnalysisConcurrency
+	}
+	return min(n, maxAnalysisConcurrency)
+}
+
+// runAnalysisPool 在任务中并发分析一批简历，全部结束、任务取消后返回；progress 可以为 nil。
+// 任务暂停时 worker 不再开始新的简历；取消时正在进行的请求被中断，这些简历不计入成功或失败。
+// 设置了项目预算时，预计超出预算的简历不再分析，结束后任务标记为已取消
+func (a *App) runAnalysisPool(job *analysisJob, ids []string, cfg *AIConfig, jobCfg *JobConfig, progress func(batchProgress)) {
+	workers := min(analysisConcurrency(cfg), len(ids))
+	queue := make(chan string)
+
+	var mu sync.Mutex // 保证进度事件按 Current 递增的顺序发出
+	report := func(id, status string) {
+		mu.Lock()
+		defer mu.Unlock()
+		info := job.snapshot()
+		switch status {
+		case "done":
+			info = job.record(true)
+		case "error":
+			info = job.record(false)
+		}
+		if progress != nil {
+			progress(batchProgress{
+				JobID: info.ID, ResumeID: id, Status: status,
+				Current: info.Done + info.Failed, Failed: info.Failed, Total: info.Total,
+			})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				if job.waitIfPaused() != nil {
+					return
+				}
+				reserved, ok := job.budget.reserve()
+				if !ok {
+					// 超出预算：剩余简历保持待分析
+					continue
+				}
+				report(id, "started")
+				before := a.resumeSpend(id)
+				// 被标记为重新分析的简历由 analyzeResume 按 Resume.ForceAnalyze 跳过缓存
+				_, err := a.analyzeResume(job.ctx, id, cfg, jobCfg, false)
+				// 按简历累计花费的增量结算，解析失败、被取消的分析同样计入
+				job.budget.settle(reserved, a.resumeSpend(id)-before)
+				if job.ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					log.Printf("分析简历 %s 失败: %v", id, err)
+					report(id, "error")
+					continue
+				}
+				report(id, "done")
+			}
+		}()
+	}
+
+feed:
+	for _, id := range ids {
+		select {
+		case <-job.ctx.Done():
+			break feed
+		case queue <- id:
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if reason := job.budget.stopReason(); reason != "" {
+		log.Printf("[runAnalysisPool] 任务 %s 停止: %s", job.info.ID, reason)
+		job.stop(reason)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================
+// 结构化输出：由 AnalysisResult 生成 JSON Schema，
+// 请求时交给服务商约束输出，返回后校验，不合格时让模型修正
+// ============================================
+
+// maxRepairAttempts 校验失败后最多发送的修正请求次数
+const maxRepairAttempts = 2
+
+// analysisSchemaName 传给服务商的 schema / 工具名
+const analysisSchemaName = "resume_analysis"
+
+// analysisLocalFields 由本地填写、不要求模型返回的字段（omitempty 的字段同样跳过）
+var analysisLocalFields = map[string]bool{"analyzed_at": true}
+
+// analysisFieldRule 字段约束
+type analysisFieldRule struct {
+	min, max float64  // 数值范围（min == max 表示不限）
+	minItems int      // 数组最少条数
+	enum     []string // 字符串可选值
+	nonEmpty bool     // 字符串不能为空
+}
+
+// analysisFieldRules 与提示词中的要求保持一致
+var analysisFieldRules = map[string]analysisFieldRule{
+	"overall_score":    {min: 0, max: 100},
+	"skill_match":      {min: 0, max: 100},
+	"experience_match": {min: 0, max: 100},
+	"education_match":  {min: 0, max: 100},
+	"recommendation":   {enum: []string{"strong_recommend", "recommend", "consider", "not_recommend"}},
+	"strengths":        {minItems: 3},
+	"weaknesses":       {minItems: 2},
+	"summary":          {nonEmpty: true},
+}
+
+// analysisField 需要模型返回的字段
+type analysisField struct {
+	name string
+	kind reflect.Kind // Float64 / String / Slice（[]string）
+	rule analysisFieldRule
+}
+
+// analysisFields 按 AnalysisResult 的字段顺序
+var analysisFields = func() []analysisField {
+	var fields []analysisField
+	t := reflect.TypeOf(AnalysisResult{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || strings.Contains(opts, "omitempty") || analysisLocalFields[name] {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Float64, reflect.String:
+		case reflect.Slice:
+			if f.Type.Elem().Kind() != reflect.String {
+				continue
+			}
+		default:
+			continue
+		}
+		fields = append(fields, analysisField{name: name, kind: f.Type.Kind(), rule: analysisFieldRules[name]})
+	}
+	return fields
+}()
+
+// analysisSchema 分析结果的 JSON Schema
+func analysisSchema() map[string]interface{} {
+	props := map[string]interface{}{}
+	required := make([]string, 0, len(analysisFields))
+	for _, f := range analysisFields {
+		prop := map[string]interface{}{}
+		switch f.kind {
+		case reflect.Float64:
+			prop["type"] = "number"
+			if f.rule.min != f.rule.max {
+				prop["minimum"] = f.rule.min
+				prop["maximum"] = f.rule.max
+			}
+		case reflect.String:
+			prop["type"] = "string"
+			if len(f.rule.enum) > 0 {
+				prop["enum"] = f.rule.enum
+			}
+		case reflect.Slice:
+			prop["type"] = "array"
+			prop["items"] = map[string]interface{}{"type": "string"}
+			if f.rule.minItems > 0 {
+				prop["minItems"] = f.rule.minItems
+			}
+		}
+		props[f.name] = prop
+		required = append(required, f.name)
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// analysisResponseFormat 分析请求使用的输出格式约束
+func analysisResponseFormat() *ResponseFormat {
+	return &ResponseFormat{Name: analysisSchemaName, Schema: analysisSchema()}
+}
+
+// validateAnalysisJSON 按 schema 校验模型返回的 JSON，返回问题列表（为空表示合格）
+func validateAnalysisJSON(data []byte) []string {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return []string{fmt.Sprintf("不是合法的 JSON 对象: %v", err)}
+	}
+
+	var problems []string
+	for _, f := range analysisFields {
+		raw, ok := obj[f.name]
+		if !ok || string(raw) == "null" {
+			problems = append(problems, fmt.Sprintf("缺少字段 %s", f.name))
+			continue
+		}
+		switch f.kind {
+		case reflect.Float64:
+			var v float64
+			if json.Unmarshal(raw, &v) != nil {
+				problems = append(problems, fmt.Sprintf("%s 必须是数字，实际为 %s", f.name, truncateRunes(string(raw), 30)))
+			} else if f.rule.min != f.rule.max && (v < f.rule.min || v > f.rule.max) {
+				problems = append(problems, fmt.Sprintf("%s 必须在 %g-%g 之间，实际为 %g", f.name, f.rule.min, f.rule.max, v))
+			}
+		case reflect.String:
+			var v string
+			switch {
+			case json.Unmarshal(raw, &v) != nil:
+				problems = append(problems, fmt.Sprintf("%s 必须是字符串", f.name))
+			case len(f.rule.enum) > 0 && !containsFold(f.rule.enum, v):
+				problems = append(problems, fmt.Sprintf("%s 只能是 %s 之一，实际为 %q", f.name, strings.Join(f.rule.enum, " / "), v))
+			case f.rule.nonEmpty && strings.TrimSpace(v) == "":
+				problems = append(problems, fmt.Sprintf("%s 不能为空", f.name))
+			}
+		case reflect.Slice:
+			var v []string
+			if json.Unmarshal(raw, &v) != nil {
+				problems = append(problems, fmt.Sprintf("%s 必须是字符串数组", f.name))
+			} else if len(v) < f.rule.minItems {
+				problems = append(problems, fmt.Sprintf("%s 至少需要 %d 条，实际为 %d 条", f.name, f.rule.minItems, len(v)))
+			}
+		}
+	}
+	return problems
+}
+
+// repairPrompt 校验失败时的修正请求
+func repairPrompt(problems []string) string {
+	return "你返回的结果不符合要求：\n- " + strings.Join(problems, "\n- ") +
+		"\n\n请修正以上问题，重新输出完整的 JSON 对象（包含所有字段），不要输出任何其他内容。"
+}
+
+// analyzeWithRepair 解析并校验模型的回复，不合格时把问题反馈给模型重新生成；
+// 多次修正仍不合格但 JSON 可以解析时，使用修正后的结果并记录校验问题，而不是判定分析失败；
+// 修正请求的用量计入 usage
+func (a *App) analyzeWithRepair(ctx context.Context, cfg *AIConfig, messages []ChatMessage, content string, usage *AIUsage) (*AnalysisResult, error) {
+	var best *AnalysisResult
+	var bestProblems []string
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		result, problems, err := a.parseAnalysisResult(content)
+		if err == nil && len(problems) == 0 {
+			return result, nil
+		}
+		if err == nil {
+			best, bestProblems = result, problems
+		} else {
+			lastErr = err
+			problems = []string{err.Error()}
+		}
+		if attempt >= maxRepairAttempts {
+			break
+		}
+
+		log.Printf("[analyzeWithRepair] 第 %d 次修正，问题: %s", attempt+1, strings.Join(problems, "; "))
+		messages = append(messages,
+			ChatMessage{Role: "assistant", Content: content},
+			ChatMessage{Role: "user", Content: repairPrompt(problems)},
+		)
+		next, used, callErr := a.callAI(ctx, cfg, messages, nil)
+		if callErr != nil {
+			log.Printf("[analyzeWithRepair] 修正请求失败: %v", callErr)
+			if best == nil {
+				return nil, callErr
+			}
+			break
+		}
+		a.recordUsage(usage, used, next)
+		content = next.Content
+	}
+
+	if best == nil {
+		return nil, lastErr
+	}
+	best.ValidationWarnings = bestProblems
+	return best, nil
+}
+
+// ============================================
+// 流式分析进度：按回复中已出现的字段估算进度，并推送部分回复
+// ============================================
+
+const (
+	analysisPromptProgress    = 20                     // 提示词构建完成、开始请求时的进度
+	analysisStreamMaxProgress = 95                     // 回复接收完毕、解析校验前的进度
+	analysisDeltaInterval     = 100 * time.Millisecond // analysis:delta 事件的最小间隔
+)
+
+// analysisStreamProgress 按部分回复中已出现的字段数估算进度（analysisPromptProgress ~ analysisStreamMaxProgress）
+func analysisStreamProgress(partial string) int {
+	if len(analysisFields) == 0 {
+		return analysisPromptProgress
+	}
+	seen := 0
+	for _, f := range analysisFields {
+		if strings.Contains(partial, `"`+f.name+`"`) {
+			seen++
+		}
+	}
+	span := analysisStreamMaxProgress - analysisPromptProgress
+	return analysisPromptProgress + span*seen/len(analysisFields)
+}
+
+// analysisStreamer 返回流式回调与 flush：节流推送 analysis:delta（增量文本、累计文本、输出 token 数）
+// 与 analysis:progress；重试导致回复重新开始时从头推送。节流期间只记录最新内容，
+// 流结束后调用 flush 推送剩余的增量与最终 token 数
+func (a *App) analysisStreamer(resumeID string) (onStream func(string, int), flush func()) {
+	var mu sync.Mutex
+	var last time.Time
+	sent, progress := 0, analysisPromptProgress
+	latest, latestTokens, dirty := "", 0, false
+
+	emit := func() {
+		last = time.Now()
+		dirty = false
+		progress = max(progress, analysisStreamProgress(latest))
+		a.emit("analysis:delta", map[string]interface{}{
+			"id":       resumeID,
+			"delta":    latest[sent:],
+			"text":     latest,
+			"tokens":   latestTokens,
+			"progress": progress,
+		})
+		a.emit("analysis:progress", map[string]interface{}{
+			"id":       resumeID,
+			"status":   "analyzing",
+			"progress": progress,
+		})
+		sent = len(latest)
+	}
+
+	onStream = func(partial string, tokens int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(partial) < sent {
+			sent = 0
+		}
+		latest, latestTokens, dirty = partial, tokens, true
+		if time.Since(last) < analysisDeltaInterval {
+			return
+		}
+		emit()
+	}
+	flush = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if dirty {
+			emit()
+		}
+	}
+	return onStream, flush
+}
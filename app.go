@@ -13,7 +13,9 @@ import (
 	"path/filepath"
 	"math"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ledongthuc/pdf"
@@ -33,18 +35,34 @@ var assets embed.FS
 
 // Config 配置结构
 type Config struct {
-	AI   AIConfig   `json:"ai"`
-	Job  JobConfig  `json:"job"`
+	AI      AIConfig      `json:"ai"`
+	Job     JobConfig     `json:"job"`
+	OCR     OCRConfig     `json:"ocr"`
+	Extract ExtractConfig `json:"extract"`
+	Import  ImportConfig  `json:"import"`
+	IMAP    IMAPConfig    `json:"imap"`
+
+	AIProfiles []AIConfig   `json:"ai_profiles,omitempty"` // 备用 AI 配置，主配置不可用时按顺序切换
+	Pricing    []ModelPrice `json:"pricing,omitempty"`     // 模型价格，优先于内置价格表
 }
 
 // AIConfig AI配置
 type AIConfig struct {
-	Provider   string `json:"provider"`
+	Name       string `json:"name,omitempty"` // 配置名称，用于区分多个备用配置
+	Provider   string `json:"provider"`       // anthropic / gemini / azure / ollama，其余按 OpenAI 兼容接口处理
 	BaseURL    string `json:"base_url"`
 	APIKey     string `json:"api_key"`
 	Model      string `json:"model"`
 	MaxRetries int    `json:"max_retries"`
 	Timeout    int    `json:"timeout"`
+	Deployment string `json:"deployment,omitempty"`  // Azure OpenAI 部署名，为空时使用 Model
+	APIVersion string `json:"api_version,omitempty"` // Azure OpenAI api-version
+
+	Concurrency       int `json:"concurrency,omitempty"` // 批量分析并发数，0 表示默认值
+	RequestsPerMinute int `json:"rpm,omitempty"`         // 每分钟请求数上限，0 表示不限制
+	TokensPerMinute   int `json:"tpm,omitempty"`         // 每分钟 token 数上限，0 表示不限制
+
+	DisableStream bool `json:"disable_stream,omitempty"` // 关闭流式输出（服务商或代理不支持 SSE 时）
 }
 
 // JobConfig 岗位配置
@@ -58,28 +76,43 @@ type JobConfig struct {
 
 // Project 招聘项目
 type Project struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	JobConfig JobConfig `json:"job_config"`
-	ResumeIDs []string  `json:"resume_ids"`
-	Status    string    `json:"status"` // draft/analyzing/completed
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	JobConfig JobConfig    `json:"job_config"`
+	ResumeIDs []string     `json:"resume_ids"`
+	Status    string       `json:"status"`          // draft/analyzing/completed
+	Inbox     *InboxConfig `json:"inbox,omitempty"` // 自动导入的收件箱文件夹
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	BudgetLimit float64 `json:"budget_limit,omitempty"` // 分析费用上限（美元），0 表示不限制
 }
 
 // Resume 简历结构
 type Resume struct {
-	ID        string          `json:"id"`
-	ProjectID string          `json:"project_id"`
-	FileName  string          `json:"file_name"`
-	FilePath  string          `json:"file_path"`
-	FileType  string          `json:"file_type"`
-	FileSize  int64           `json:"file_size"`
-	Content   string          `json:"content"`
-	Status    string          `json:"status"`
-	Score     int             `json:"score"`
-	Analysis  *AnalysisResult `json:"analysis,omitempty"`
-	CreatedAt time.Time       `json:"created_at"`
+	ID              string            `json:"id"`
+	ProjectID       string            `json:"project_id"`
+	FileName        string            `json:"file_name"`
+	FilePath        string            `json:"file_path"`               // 托管副本路径（files/<hash>/），托管失败时为原始位置
+	OriginalPath    string            `json:"original_path,omitempty"` // 导入时用户的原始文件位置
+	FileType        string            `json:"file_type"`
+	FileSize        int64             `json:"file_size"`
+	FileHash        string            `json:"file_hash,omitempty"` // 原始文件 SHA-256
+	Content         string            `json:"content"`
+	TextFingerprint string            `json:"text_fingerprint,omitempty"` // 归一化文本 SHA-256
+	SimHash         string            `json:"simhash,omitempty"`          // 归一化文本 SimHash（十六进制）
+	ExtractError    string            `json:"extract_error,omitempty"`    // 文件无法解析的原因（加密、损坏等）
+	Extraction      *ExtractionReport `json:"extraction,omitempty"`       // 文本提取质量报告
+	Sections        []ResumeSection   `json:"sections,omitempty"`         // 按标题切分的分节
+	Contact         *ContactInfo      `json:"contact,omitempty"`          // 本地解析的联系方式
+	Duplicate       *DuplicateMatch   `json:"duplicate,omitempty"`        // 导入时发现的重复简历
+	Email           *EmailSource      `json:"email,omitempty"`            // 通过邮件导入时的来源邮件
+	Status          string            `json:"status"`                     // pending / analyzing / done / error / duplicate（待确认的重复简历）
+	Score           int               `json:"score"`
+	Analysis        *AnalysisResult   `json:"analysis,omitempty"`
+	Spend           *AIUsage          `json:"spend,omitempty"`         // 历次分析的累计用量与费用（含失败与被覆盖的分析）
+	ForceAnalyze    bool              `json:"force_analyze,omitempty"` // 用户要求重新分析：下次分析不使用缓存，完成后清除
+	CreatedAt       time.Time         `json:"created_at"`
 }
 
 // AnalysisResult AI分析结果
@@ -110,27 +143,50 @@ type AnalysisResult struct {
 	// 面试建议
 	InterviewSuggestions []string `json:"interview_suggestions"`
 
+	// 简历文本提取质量偏低时的提示（评分可能不可靠）
+	ExtractionWarning string `json:"extraction_warning,omitempty"`
+	// 与本地解析结果不一致之处
+	CrossCheckWarnings []string `json:"cross_check_warnings,omitempty"`
+	// 模型多次修正后仍未通过校验的问题（结果已按规则修正）
+	ValidationWarnings []string `json:"validation_warnings,omitempty"`
+	// 提示词 token 预算，记录因篇幅省略的简历内容
+	Prompt *PromptBudget `json:"prompt,omitempty"`
+	// 实际完成分析的 AI 配置（主配置不可用时为备用配置）与模型
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// 本次分析（含修正请求）的 token 用量与费用
+	Usage *AIUsage `json:"usage,omitempty"`
+	// 结果来自缓存，没有调用 AI
+	Cached bool `json:"cached,omitempty"`
+
 	AnalyzedAt string `json:"analyzed_at"`
 }
 
-// OpenAI API 请求/响应结构
+// ChatRequest 对话请求，由各服务商适配器转换为各自的接口格式（见 provider.go）
 type ChatRequest struct {
 	Model       string        `json:"model"`
 	Messages    []ChatMessage `json:"messages"`
 	Temperature float64       `json:"temperature,omitempty"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
+
+	ResponseFormat *ResponseFormat                        `json:"-"` // 要求按 JSON Schema 输出，服务商不支持时忽略
+	OnStream       func(partial string, outputTokens int) `json:"-"` // 非空时使用流式输出，收到累计的部分回复与输出 token 数
 }
 
 type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string      `json:"role"`
+	Content string      `json:"content"`
+	Images  []ChatImage `json:"-"` // 随消息发送的图片，仅视觉模型支持
 }
 
+// ChatResponse OpenAI 兼容接口的响应
 type ChatResponse struct {
 	ID      string `json:"id"`
+	Model   string `json:"model"`
 	Choices []struct {
 		Message ChatMessage `json:"message"`
 	} `json:"choices"`
+	Usage *openAIUsage `json:"usage,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
@@ -141,6 +197,31 @@ type App struct {
 	ctx             context.Context
 	config          Config
 	activeProjectID string // 当前活跃的项目ID（前端设置）
+
+	configMu sync.RWMutex // 保护 config：后台任务（收件箱、IMAP、分析）与 SaveConfig 并发读写，见 currentConfig()
+
+	projectLocks sync.Map // 项目ID -> *sync.Mutex，见 modifyProject()
+
+	inboxMu    sync.Mutex
+	inboxStops map[string]context.CancelFunc // 项目ID -> 停止收件箱轮询
+
+	imapMu   sync.Mutex
+	imapStop context.CancelFunc // 停止 IMAP 轮询
+	imapDial imapDialer         // 为空时使用 dialIMAP，测试时可替换为本地实现
+
+	imapPollMu sync.Mutex // 串行化 IMAP 轮询，见 syncIMAP()
+
+	extractorsOnce sync.Once
+	extractorReg   *extractorRegistry // 文本提取器注册表，见 extractors()
+
+	limitersMu sync.Mutex
+	limiters   map[string]*providerLimiter // 服务商 -> 限流器，见 rateLimiter()
+
+	jobsMu sync.Mutex
+	jobs   map[string]*analysisJob // 任务ID -> 正在运行的分析任务
+
+	breakersMu sync.Mutex
+	breakers   map[string]*providerBreaker // 服务商+模型 -> 熔断状态，见 recordBreaker()
 }
 
 func NewApp() *App {
@@ -163,7 +244,8 @@ func main() {
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
-		OnStartup: app.startup,
+		OnStartup:  app.startup,
+		OnShutdown: app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -193,45 +275,27 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	a.loadConfig()
 
-	// 监听原生文件拖拽（Wails 提供真实文件路径）
+	// 监听原生文件拖拽（Wails 提供真实文件路径，可以是文件、文件夹或 .zip）
 	runtime.OnFileDrop(ctx, func(x, y int, paths []string) {
-		log.Printf("[OnFileDrop] 收到 %d 个文件, 项目=%s", len(paths), a.activeProjectID)
-		supportedExts := map[string]bool{
-			".pdf": true, ".docx": true, ".doc": true,
-			".jpg": true, ".jpeg": true, ".png": true, ".bmp": true, ".gif": true, ".webp": true,
-		}
-		added := 0
-		for _, fp := range paths {
-			ext := strings.ToLower(filepath.Ext(fp))
-			if !supportedExts[ext] {
-				continue
-			}
-			info, err := os.Stat(fp)
-			if err != nil {
-				continue
-			}
-			id := fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(fp))
-
-			if a.activeProjectID != "" {
-				a.RegisterResumeToProject(a.activeProjectID, id, filepath.Base(fp), fp, ext, info.Size())
-			} else {
-				a.RegisterResume(id, filepath.Base(fp), fp, ext, info.Size())
-			}
-
-			// 通知前端（包含提取的内容）
-			resumePath := filepath.Join(a.getDataDir(), "resumes", id+".json")
-			data, _ := os.ReadFile(resumePath)
-			var resume Resume
-			json.Unmarshal(data, &resume)
-			runtime.EventsEmit(a.ctx, "resume:dropped", &resume)
-			added++
-		}
-		log.Printf("[OnFileDrop] 成功添加 %d 个文件", added)
+		log.Printf("[OnFileDrop] 收到 %d 个路径, 项目=%s", len(paths), a.activeProjectID)
+		summary := a.importPaths(a.activeProjectID, paths)
+		log.Printf("[OnFileDrop] 成功添加 %d 个文件", summary.Accepted)
 	})
 
+	a.startInboxWatchers()
+	a.startIMAPPoller()
+
 	log.Println("TalentLens 已启动")
 }
 
+// emit 向前端发送事件；未在 Wails 中运行（a.ctx 为空，如测试）时跳过
+func (a *App) emit(event string, data ...interface{}) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(a.ctx, event, data...)
+}
+
 // SetActiveProject 前端告知后端当前活跃项目
 func (a *App) SetActiveProject(projectID string) {
 	a.activeProjectID = projectID
@@ -241,42 +305,14 @@ func (a *App) SetActiveProject(projectID string) {
 // SelectResumeFiles 打开原生文件选择对话框，返回添加数量
 func (a *App) SelectResumeFiles(projectID string) int {
 	files, err := runtime.OpenMultipleFilesDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "选择简历文件",
-		Filters: []runtime.FileFilter{
-			{DisplayName: "简历文件 (PDF/Word/图片)", Pattern: "*.pdf;*.docx;*.doc;*.jpg;*.jpeg;*.png;*.bmp;*.gif;*.webp"},
-			{DisplayName: "PDF 文件", Pattern: "*.pdf"},
-			{DisplayName: "Word 文件", Pattern: "*.docx;*.doc"},
-			{DisplayName: "图片文件", Pattern: "*.jpg;*.jpeg;*.png;*.bmp;*.gif;*.webp"},
-			{DisplayName: "所有文件", Pattern: "*.*"},
-		},
+		Title:   "选择简历文件",
+		Filters: a.resumeFileFilters(),
 	})
 	if err != nil || len(files) == 0 {
 		return 0
 	}
 
-	count := 0
-	for _, fp := range files {
-		info, err := os.Stat(fp)
-		if err != nil {
-			continue
-		}
-		ext := strings.ToLower(filepath.Ext(fp))
-		id := fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(fp))
-
-		if projectID != "" {
-			a.RegisterResumeToProject(projectID, id, filepath.Base(fp), fp, ext, info.Size())
-		} else {
-			a.RegisterResume(id, filepath.Base(fp), fp, ext, info.Size())
-		}
-
-		// 通知前端
-		resumePath := filepath.Join(a.getDataDir(), "resumes", id+".json")
-		data, _ := os.ReadFile(resumePath)
-		var resume Resume
-		json.Unmarshal(data, &resume)
-		runtime.EventsEmit(a.ctx, "resume:dropped", &resume)
-		count++
-	}
+	count := a.importPaths(projectID, files).Accepted
 
 	log.Printf("[SelectResumeFiles] 选择了 %d 个文件", count)
 	return count
@@ -329,6 +365,17 @@ func (a *App) loadConfig() {
 				Title:          "高级Go开发工程师",
 				RequiredSkills: []string{"Go", "MySQL", "Redis"},
 			},
+			OCR: OCRConfig{
+				Engine:    "none",
+				Languages: "chi_sim+eng",
+				Timeout:   120,
+			},
+			Extract: ExtractConfig{
+				PDFStrategy: "layout",
+			},
+			Import: ImportConfig{
+				DuplicatePolicy: DuplicateLink,
+			},
 		}
 		return
 	}
@@ -340,7 +387,10 @@ func (a *App) processFile(filePath string) {
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	// 简单解析文本
-	content := a.extractText(filePath)
+	content, report, extractErr := a.extractText(context.Background(), filePath)
+	if extractErr != nil {
+		log.Printf("[processFile] 提取失败: %v", extractErr)
+	}
 
 	resume := &Resume{
 		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
@@ -348,88 +398,122 @@ func (a *App) processFile(filePath string) {
 		FilePath:  filePath,
 		FileType:  ext,
 		FileSize:  info.Size(),
-		Content:   content,
 		Status:    "pending",
 		CreatedAt: time.Now(),
 	}
+	setResumeContent(resume, content, report)
+	if extractErr != nil {
+		resume.ExtractError = extractErr.Error()
+	}
+	a.adoptOriginal(resume)
 
 	// 保存
 	a.saveResume(resume)
 
 	// 发送到前端
-	runtime.EventsEmit(a.ctx, "resume:added", resume)
+	a.emit("resume:added", resume)
 }
 
-func (a *App) extractText(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
+// extractText 按扩展名提取文本并生成质量报告；返回的 error 仅表示文件本身无法读取（加密、损坏等）
+// 或 ctx 已取消，普通的提取为空仍返回 ("", report, nil)，由调用方使用占位内容；
+// ctx 取消时中断 OCR、pdftoppm 等耗时的外部程序
+func (a *App) extractText(ctx context.Context, filePath string) (string, *ExtractionReport, error) {
+	report := &ExtractionReport{}
+	content, err := a.extractContent(ctx, filePath, report)
+	if content != "" {
+		assessExtraction(report, content)
+	}
+	return content, report, err
+}
 
-	switch ext {
-	case ".txt", ".md":
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			return ""
-		}
-		return a.truncateContent(string(data), 50000)
-	case ".pdf":
-		content := a.extractFromPDF(filePath)
-		if content != "" {
-			return content
-		}
-		// PDF 库提取失败时回退到原始方式
-		log.Println("[extractText] PDF 库提取失败，回退到原始方式")
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			return ""
-		}
-		return a.extractFromBytes(data)
-	default:
-		data, err := os.ReadFile(filePath)
+// extractContent 按文件内容从提取器注册表中选择提取器，并记录提取器名称、识别出的类型与页数
+func (a *App) extractContent(ctx context.Context, filePath string, report *ExtractionReport) (string, error) {
+	e, mime := a.extractors().detect(filePath)
+	report.DetectedType = mime
+	if e == nil {
+		report.Extractor = "raw"
+		return a.extractRawText(filePath), nil
+	}
+	report.Extractor = e.Name()
+	return a.runExtractor(ctx, e, filePath, report)
+}
+
+// loadResumeContent 注册简历时提取内容；提取为空时使用文件名占位，并在报告中标记
+func (a *App) loadResumeContent(fileName, filePath, fileType string, fileSize int64) (string, *ExtractionReport, string) {
+	content := ""
+	extractErr := ""
+	report := &ExtractionReport{}
+	if filePath != "" && filePath != fileName {
+		// 有真实路径，尝试读取文件内容
+		var err error
+		content, report, err = a.extractText(context.Background(), filePath)
 		if err != nil {
-			return ""
+			extractErr = err.Error()
+			log.Printf("[loadResumeContent] 提取失败: %s, %v", fileName, err)
 		}
-		return a.extractFromBytes(data)
+		log.Printf("[loadResumeContent] 提取内容长度: %d", len(content))
 	}
+
+	if content == "" {
+		content = fmt.Sprintf("[简历文件: %s, 类型: %s, 大小: %d bytes]", fileName, fileType, fileSize)
+		report.UsedPlaceholder = true
+		assessExtraction(report, content)
+		log.Printf("[loadResumeContent] 使用占位内容: %s", fileName)
+	}
+	return content, report, extractErr
 }
 
 // extractFromPDF 使用 ledongthuc/pdf 库提取 PDF 文本（支持中文）
-func (a *App) extractFromPDF(filePath string) string {
+// 返回提取的文本和页数
+func (a *App) extractFromPDF(filePath string) (string, int) {
 	f, r, err := pdf.Open(filePath)
 	if err != nil {
 		log.Printf("[extractFromPDF] 打开 PDF 失败: %v", err)
-		return ""
+		return "", 0
 	}
 	defer f.Close()
+	pages := r.NumPage()
 
-	var buf bytes.Buffer
-	reader, err := r.GetPlainText()
-	if err != nil {
-		log.Printf("[extractFromPDF] 提取文本失败: %v", err)
-		return ""
+	// 默认按版面提取（处理双栏、保留分节空行），失败或为空时回退到纯文本
+	result := ""
+	if a.currentConfig().Extract.PDFStrategy != "plain" {
+		layout, err := pdfLayoutText(r)
+		if err != nil {
+			log.Printf("[extractFromPDF] 版面提取失败，回退到纯文本: %v", err)
+		}
+		result = strings.TrimSpace(layout)
 	}
-	buf.ReadFrom(reader)
 
-	content := strings.TrimSpace(buf.String())
-	if content == "" {
-		log.Printf("[extractFromPDF] PDF 提取结果为空: %s", filePath)
-		return ""
-	}
+	if result == "" {
+		var buf bytes.Buffer
+		reader, err := r.GetPlainText()
+		if err != nil {
+			log.Printf("[extractFromPDF] 提取文本失败: %v", err)
+			return "", pages
+		}
+		buf.ReadFrom(reader)
 
-	// 清理多余空白行
-	lines := strings.Split(content, "\n")
-	var cleaned []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			cleaned = append(cleaned, line)
+		content := strings.TrimSpace(buf.String())
+		if content == "" {
+			log.Printf("[extractFromPDF] PDF 提取结果为空: %s", filePath)
+			return "", pages
+		}
+
+		// 清理多余空白行
+		lines := strings.Split(content, "\n")
+		var cleaned []string
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				cleaned = append(cleaned, line)
+			}
 		}
+		result = strings.Join(cleaned, "\n")
 	}
-	result := strings.Join(cleaned, "\n")
 
 	log.Printf("[extractFromPDF] 提取成功: %s, 长度=%d 字符", filepath.Base(filePath), len(result))
-	if len(result) > 50000 {
-		result = result[:50000]
-	}
-	return result
+	result = limitContent(result)
+	return result, pages
 }
 
 func (a *App) extractFromBytes(data []byte) string {
@@ -443,12 +527,23 @@ func (a *App) extractFromBytes(data []byte) string {
 		}
 	}
 	result := strings.Join(clean, "\n")
-	if len(result) > 50000 {
-		result = result[:50000]
-	}
+	result = limitContent(result)
 	return result
 }
 
+// setResumeContent 更新简历内容以及由内容派生的字段（质量报告、分节、联系方式、去重指纹）
+func setResumeContent(r *Resume, content string, report *ExtractionReport) {
+	r.Content = content
+	r.Extraction = report
+	r.Sections = resumeSections(content, report)
+	if report != nil && report.UsedPlaceholder {
+		r.Contact = &ContactInfo{Name: guessNameFromFileName(r.FileName)}
+	} else {
+		r.Contact = extractContactInfo(content, r.Sections, r.FileName)
+	}
+	setResumeFingerprints(r)
+}
+
 func (a *App) saveResume(r *Resume) {
 	dir := filepath.Join(a.getDataDir(), "resumes")
 	os.MkdirAll(dir, 0755)
@@ -460,44 +555,76 @@ func (a *App) saveResume(r *Resume) {
 // 前端通过 HTML5 拖拽添加文件时，后端无感知，需要前端主动调用此方法
 func (a *App) RegisterResume(id string, fileName string, filePath string, fileType string, fileSize int64) (bool, string) {
 	log.Printf("[RegisterResume] id=%s, file=%s, path=%s", id, fileName, filePath)
+	return a.registerResume("", id, fileName, filePath, fileType, fileSize, a.newDuplicateIndex())
+}
 
+// registerResume 提取内容、查重并保存简历；有项目时加入项目的简历列表。
+// dups 为已有简历的查重索引，批量导入时整批共用，保存的简历会加入索引
+func (a *App) registerResume(projectID, id, fileName, filePath, fileType string, fileSize int64, dups *duplicateIndex) (bool, string) {
 	// 提取文件内容
-	content := ""
-	if filePath != "" && filePath != fileName {
-		// 有真实路径，尝试读取文件内容
-		content = a.extractText(filePath)
-		log.Printf("[RegisterResume] 提取内容长度: %d", len(content))
-	}
-
-	if content == "" {
-		content = fmt.Sprintf("[简历文件: %s, 类型: %s, 大小: %d bytes]", fileName, fileType, fileSize)
-		log.Printf("[RegisterResume] 使用占位内容")
-	}
+	content, report, extractErr := a.loadResumeContent(fileName, filePath, fileType, fileSize)
 
 	resume := &Resume{
-		ID:        id,
-		FileName:  fileName,
-		FilePath:  filePath,
-		FileType:  fileType,
-		FileSize:  fileSize,
-		Content:   content,
-		Status:    "pending",
-		CreatedAt: time.Now(),
-	}
+		ID:           id,
+		ProjectID:    projectID,
+		FileName:     fileName,
+		FilePath:     filePath,
+		FileType:     fileType,
+		FileSize:     fileSize,
+		ExtractError: extractErr,
+		Status:       "pending",
+		CreatedAt:    time.Now(),
+	}
+	setResumeContent(resume, content, report)
+	if !a.dedupeResume(resume, dups) {
+		return false, "重复简历，已跳过: " + fileName
+	}
+	a.adoptOriginal(resume)
 
 	a.saveResume(resume)
-	log.Printf("[RegisterResume] 简历已保存: %s", id)
+	dups.add(resume)
+	a.notifyDuplicate(resume)
+	log.Printf("[registerResume] 简历已保存: %s", id)
+
+	// 更新项目的简历列表
+	if projectID != "" {
+		a.modifyProject(projectID, func(p *Project) {
+			p.ResumeIDs = append(p.ResumeIDs, id)
+		})
+	}
 	return true, "简历已注册: " + fileName
 }
 
 func (a *App) GetConfig() *Config {
-	return &a.config
+	cfg := a.currentConfig()
+	return &cfg
+}
+
+// currentConfig 当前配置的副本；后台 goroutine 读取配置时使用，避免与 SaveConfig 竞争
+func (a *App) currentConfig() Config {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	return a.config
 }
 
 func (a *App) SaveConfig(cfg *Config) error {
+	a.configMu.Lock()
+	imapChanged := a.config.IMAP != cfg.IMAP
+	aiChanged := a.config.AI != cfg.AI || !slices.Equal(a.config.AIProfiles, cfg.AIProfiles)
 	a.config = *cfg
+	a.configMu.Unlock()
 	data, _ := json.MarshalIndent(cfg, "", "  ")
-	return os.WriteFile(a.getConfigPath(), data, 0644)
+	if err := os.WriteFile(a.getConfigPath(), data, 0644); err != nil {
+		return err
+	}
+	if imapChanged {
+		a.restartIMAPPoller()
+	}
+	if aiChanged {
+		// 正在运行的任务使用的是旧配置，停止以免继续按旧配置计费
+		a.cancelJobs(func(JobInfo) bool { return true }, "AI 配置已变更")
+	}
+	return nil
 }
 
 func (a *App) GetResumes() []*Resume {
@@ -519,6 +646,9 @@ func (a *App) GetResumes() []*Resume {
 }
 
 func (a *App) DeleteResume(id string) error {
+	if r := a.loadResume(id); r != nil {
+		a.releaseOriginal(r)
+	}
 	path := filepath.Join(a.getDataDir(), "resumes", id+".json")
 	return os.Remove(path)
 }
@@ -529,9 +659,10 @@ func (a *App) ReAnalyzeResume(id string) error {
 	var r Resume
 	json.Unmarshal(data, &r)
 	r.Status = "pending"
+	r.ForceAnalyze = true // 之后无论单个分析还是批量分析都重新调用 AI
 	data, _ = json.MarshalIndent(r, "", "  ")
 	os.WriteFile(path, data, 0644)
-	runtime.EventsEmit(a.ctx, "resume:updated", &r)
+	a.emit("resume:updated", &r)
 	return nil
 }
 
@@ -539,6 +670,7 @@ func (a *App) ClearResumes() error {
 	dir := filepath.Join(a.getDataDir(), "resumes")
 	os.RemoveAll(dir)
 	os.MkdirAll(dir, 0755)
+	os.RemoveAll(filepath.Join(a.getDataDir(), "files"))
 	return nil
 }
 
@@ -565,11 +697,19 @@ func (a *App) GetFreshResumeContent(id string) (string, error) {
 		return "", fmt.Errorf("解析失败")
 	}
 
-	// 重新从原始文件提取
-	if resume.FilePath != "" && resume.FilePath != resume.FileName {
-		freshContent := a.extractText(resume.FilePath)
-		if freshContent != "" && len(freshContent) > 10 {
-			resume.Content = freshContent
+	// 重新从原始文件提取；旧版本导入的简历在原文件仍存在时补做托管
+	if a.adoptOriginal(&resume) {
+		a.saveResume(&resume)
+	}
+	if src := existingResumeFile(&resume); src != "" {
+		freshContent, report, err := a.extractText(context.Background(), src)
+		if err != nil {
+			log.Printf("[GetFreshResumeContent] 重新提取失败: %s, %v", resume.FileName, err)
+			resume.ExtractError = err.Error()
+			a.saveResume(&resume)
+		} else if freshContent != "" && len(freshContent) > 10 {
+			resume.ExtractError = ""
+			setResumeContent(&resume, freshContent, report)
 			a.saveResume(&resume) // 更新磁盘缓存
 			log.Printf("[GetFreshResumeContent] 重新提取成功: %s, 长度=%d", resume.FileName, len(freshContent))
 			return freshContent, nil
@@ -653,13 +793,44 @@ func (a *App) GetProject(id string) *Project {
 
 // UpdateProject 更新项目
 func (a *App) UpdateProject(p *Project) error {
+	mu := a.projectLock(p.ID)
+	mu.Lock()
+	defer mu.Unlock()
 	p.UpdatedAt = time.Now()
 	a.saveProject(p)
 	return nil
 }
 
+// projectLock 项目的读-改-写锁
+func (a *App) projectLock(id string) *sync.Mutex {
+	mu, _ := a.projectLocks.LoadOrStore(id, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// modifyProject 在项目锁内重新读取项目、修改并保存，返回保存后的项目；项目不存在时返回 nil。
+// 导入、收件箱、IMAP 与分析任务会并发修改同一项目，直接 GetProject + UpdateProject 会互相覆盖
+func (a *App) modifyProject(id string, fn func(p *Project)) *Project {
+	mu := a.projectLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+	p := a.GetProject(id)
+	if p == nil {
+		return nil
+	}
+	fn(p)
+	p.UpdatedAt = time.Now()
+	a.saveProject(p)
+	return p
+}
+
 // DeleteProject 删除项目及其关联简历
 func (a *App) DeleteProject(id string) error {
+	a.cancelJobs(func(j JobInfo) bool { return j.ProjectID == id }, "项目已删除")
+	a.stopInbox(id)
+	os.Remove(a.inboxStatePath(id))
+	mu := a.projectLock(id)
+	mu.Lock()
+	defer mu.Unlock()
 	p := a.GetProject(id)
 	if p != nil {
 		// 删除关联简历
@@ -732,125 +903,108 @@ func (a *App) GetProjectStats(projectID string) map[string]interface{} {
 		avgScore = totalScore / analyzed
 	}
 
+	// 费用统计：按简历的累计花费计算，包括失败与重新分析的请求
+	var usage AIUsage
+	charged := 0
+	for _, r := range resumes {
+		if r.Spend != nil {
+			usage.add(*r.Spend)
+			charged++
+		}
+	}
+	avgCost := 0.0
+	if charged > 0 {
+		avgCost = usage.Cost / float64(charged)
+	}
+	budgetLimit := 0.0
+	if p := a.GetProject(projectID); p != nil {
+		budgetLimit = p.BudgetLimit
+	}
+
 	return map[string]interface{}{
-		"total":       total,
-		"analyzed":    analyzed,
-		"avgScore":    avgScore,
-		"maxScore":    maxScore,
-		"recommended": recommended,
+		"total":            total,
+		"analyzed":         analyzed,
+		"avgScore":         avgScore,
+		"maxScore":         maxScore,
+		"recommended":      recommended,
+		"totalCost":        usage.Cost,
+		"avgCost":          avgCost,
+		"promptTokens":     usage.PromptTokens,
+		"completionTokens": usage.CompletionTokens,
+		"usageEstimated":   usage.Estimated,
+		"budgetLimit":      budgetLimit,
 	}
 }
 
 // RegisterResumeToProject 注册简历到项目
 func (a *App) RegisterResumeToProject(projectID string, id string, fileName string, filePath string, fileType string, fileSize int64) (bool, string) {
 	log.Printf("[RegisterResumeToProject] proj=%s, file=%s", projectID, fileName)
-
-	content := ""
-	if filePath != "" && filePath != fileName {
-		content = a.extractText(filePath)
-	}
-	if content == "" {
-		content = fmt.Sprintf("[简历文件: %s, 类型: %s, 大小: %d bytes]", fileName, fileType, fileSize)
-	}
-
-	resume := &Resume{
-		ID:        id,
-		ProjectID: projectID,
-		FileName:  fileName,
-		FilePath:  filePath,
-		FileType:  fileType,
-		FileSize:  fileSize,
-		Content:   content,
-		Status:    "pending",
-		CreatedAt: time.Now(),
-	}
-	a.saveResume(resume)
-
-	// 更新项目的简历列表
-	p := a.GetProject(projectID)
-	if p != nil {
-		p.ResumeIDs = append(p.ResumeIDs, id)
-		a.UpdateProject(p)
-	}
-
-	return true, "简历已注册: " + fileName
+	return a.registerResume(projectID, id, fileName, filePath, fileType, fileSize, a.newDuplicateIndex())
 }
 
-// ImportResumesToProject 批量导入简历文件到项目
+// ImportResumesToProject 批量导入简历到项目，路径可以是文件、文件夹（递归）或 .zip 压缩包
 func (a *App) ImportResumesToProject(projectID string, filePaths []string) (int, error) {
-	count := 0
-	supportedExts := map[string]bool{
-		".pdf": true, ".docx": true, ".doc": true,
-		".jpg": true, ".jpeg": true, ".png": true, ".bmp": true, ".gif": true, ".webp": true,
-	}
-
-	for _, fp := range filePaths {
-		ext := strings.ToLower(filepath.Ext(fp))
-		if !supportedExts[ext] {
-			continue
-		}
-		info, err := os.Stat(fp)
-		if err != nil {
-			continue
-		}
-		id := fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(fp))
-		a.RegisterResumeToProject(projectID, id, filepath.Base(fp), fp, ext, info.Size())
-		count++
-	}
-	return count, nil
+	return a.importPaths(projectID, filePaths).Accepted, nil
 }
 
-// StartProjectAnalysis 对项目中所有待分析的简历进行批量分析
-func (a *App) StartProjectAnalysis(projectID string, cfg *AIConfig) {
-	if cfg == nil || cfg.APIKey == "" {
+// StartProjectAnalysis 对项目中所有待分析的简历进行批量分析，返回任务ID（可用于取消、暂停）
+func (a *App) StartProjectAnalysis(projectID string, cfg *AIConfig) string {
+	if err := checkAIConfig(cfg); err != nil {
 		log.Println("[StartProjectAnalysis] AI 未配置，终止")
-		runtime.EventsEmit(a.ctx, "analysis:error", map[string]interface{}{
+		a.emit("analysis:error", map[string]interface{}{
 			"id":    "",
-			"error": "AI 未配置: 请先在设置中填写 API Key",
+			"error": err.Error(),
 		})
-		return
+		return ""
 	}
 	p := a.GetProject(projectID)
 	if p == nil {
-		return
+		return ""
 	}
 
-	go func() {
-		p.Status = "analyzing"
-		a.saveProject(p)
-
-		resumes := a.GetProjectResumes(projectID)
-		var pendingIDs []string
-		for _, r := range resumes {
-			if r.Status == "pending" || r.Status == "error" {
-				pendingIDs = append(pendingIDs, r.ID)
-			}
+	resumes := a.GetProjectResumes(projectID)
+	var pendingIDs []string
+	var pending []*Resume
+	for _, r := range resumes {
+		if r.Status == "pending" || r.Status == "error" {
+			pendingIDs = append(pendingIDs, r.ID)
+			pending = append(pending, r)
 		}
+	}
 
-		total := len(pendingIDs)
-		for i, id := range pendingIDs {
-			runtime.EventsEmit(a.ctx, "batch:progress", map[string]interface{}{
-				"current":   i + 1,
-				"total":     total,
-				"resumeId":  id,
-				"projectId": projectID,
-			})
-
-			_, err := a.AnalyzeResume(id, cfg, &p.JobConfig)
-			if err != nil {
-				log.Printf("分析简历 %s 失败: %v", id, err)
-			}
-			time.Sleep(500 * time.Millisecond)
-		}
+	// 项目预算：已达上限时不开始，运行中预计超出时停止
+	budget, err := a.newBudgetGuard(p, cfg, pending)
+	if err != nil {
+		log.Printf("[StartProjectAnalysis] %v", err)
+		a.emit("analysis:error", map[string]interface{}{
+			"id":    "",
+			"error": err.Error(),
+		})
+		return ""
+	}
 
-		p.Status = "completed"
-		a.saveProject(p)
+	// 先标记为分析中再登记任务，避免任务很快结束时 "completed" 被覆盖；
+	// 登记失败说明项目已有任务在运行，状态本来就是分析中
+	prevStatus := p.Status
+	a.modifyProject(projectID, func(p *Project) { p.Status = "analyzing" })
 
-		runtime.EventsEmit(a.ctx, "batch:completed", map[string]interface{}{
-			"total":     total,
-			"projectId": projectID,
+	job, err := a.startJob(context.Background(), projectID, pendingIDs, cfg, &p.JobConfig, budget, func(info JobInfo) {
+		// 在项目锁内重新读取项目：分析期间可能导入了新简历，项目也可能已被删除
+		a.modifyProject(projectID, func(latest *Project) {
+			latest.Status = "completed"
+			if info.Status == JobCancelled {
+				latest.Status = prevStatus
+			}
+		})
+	})
+	if err != nil {
+		a.emit("analysis:error", map[string]interface{}{
+			"id":    "",
+			"error": err.Error(),
 		})
-	}()
+		return ""
+	}
+	return job.info.ID
 }
 
 // MigrateExistingResumes 将现有简历迁移到默认项目
@@ -867,15 +1021,18 @@ func (a *App) MigrateExistingResumes() string {
 	}
 
 	// 创建默认项目
-	jobCfg := &a.config.Job
-	p := a.CreateProject("默认项目", jobCfg)
+	jobCfg := a.currentConfig().Job
+	p := a.CreateProject("默认项目", &jobCfg)
 
 	for _, r := range resumes {
 		r.ProjectID = p.ID
 		a.saveResume(r)
-		p.ResumeIDs = append(p.ResumeIDs, r.ID)
 	}
-	a.saveProject(p)
+	a.modifyProject(p.ID, func(p *Project) {
+		for _, r := range resumes {
+			p.ResumeIDs = append(p.ResumeIDs, r.ID)
+		}
+	})
 	log.Printf("[MigrateExistingResumes] 迁移 %d 份简历到默认项目", len(resumes))
 	return p.ID
 }
@@ -987,79 +1144,34 @@ func (a *App) OpenExportDir() {
 
 // TestAIConnection 测试AI连接
 func (a *App) TestAIConnection(cfg *AIConfig) (bool, string) {
-	if cfg.APIKey == "" {
-		return false, "API Key 不能为空"
-	}
-	if cfg.BaseURL == "" {
-		return false, "Base URL 不能为空"
-	}
-
-	// 构造测试请求
-	reqBody := ChatRequest{
-		Model: cfg.Model,
-		Messages: []ChatMessage{
-			{Role: "user", Content: "Hello, this is a connection test. Please respond with 'OK'."},
-		},
-		MaxTokens: 10,
-	}
-
-	body, _ := json.Marshal(reqBody)
-	url := strings.TrimSuffix(cfg.BaseURL, "/") + "/chat/completions"
-
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
-	if err != nil {
-		return false, fmt.Sprintf("创建请求失败: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-
-	client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, fmt.Sprintf("连接失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode == 401 {
-		return false, "API Key 无效或已过期"
-	}
-	if resp.StatusCode == 403 {
-		return false, "API Key 权限不足"
-	}
-	if resp.StatusCode == 429 {
-		return false, "请求过于频繁，请稍后再试"
-	}
-	if resp.StatusCode >= 500 {
-		return false, "服务器错误，请稍后再试"
+	if err := checkAIConfig(cfg); err != nil {
+		return false, err.Error()
 	}
 
-	var chatResp ChatResponse
-	if err := json.Unmarshal(respBody, &chatResp); err != nil {
-		return false, fmt.Sprintf("解析响应失败: %v", err)
+	p := newProvider(cfg)
+	if err := p.Test(context.Background()); err != nil {
+		log.Printf("[TestAIConnection] %s 连接失败: %v", p.Name(), err)
+		return false, describeAIError(err)
 	}
-
-	if chatResp.Error != nil {
-		return false, fmt.Sprintf("AI 错误: %s", chatResp.Error.Message)
-	}
-
-	if len(chatResp.Choices) == 0 {
-		return false, "AI 没有返回任何响应"
-	}
-
 	return true, "连接成功！AI 服务正常"
 }
 
-// AnalyzeResume 分析单个简历
+// AnalyzeResume 分析单个简历；内容与配置没有变化时返回缓存的结果
 func (a *App) AnalyzeResume(resumeID string, cfg *AIConfig, jobCfg *JobConfig) (*AnalysisResult, error) {
+	return a.analyzeResume(context.Background(), resumeID, cfg, jobCfg, false)
+}
+
+// ForceAnalyzeResume 忽略缓存，重新调用 AI 分析单个简历
+func (a *App) ForceAnalyzeResume(resumeID string, cfg *AIConfig, jobCfg *JobConfig) (*AnalysisResult, error) {
+	return a.analyzeResume(context.Background(), resumeID, cfg, jobCfg, true)
+}
+
+// analyzeResume 分析单个简历；ctx 取消时中断 AI 请求，简历恢复为待分析；
+// force 为 true 或简历被标记为重新分析（ReAnalyzeResume）时不使用缓存
+func (a *App) analyzeResume(ctx context.Context, resumeID string, cfg *AIConfig, jobCfg *JobConfig, force bool) (*AnalysisResult, error) {
 	// 校验 AI 配置
-	if cfg == nil || cfg.APIKey == "" {
-		return nil, fmt.Errorf("AI 未配置: 请先在设置中填写 API Key")
-	}
-	if cfg.BaseURL == "" {
-		return nil, fmt.Errorf("AI 未配置: 请先在设置中填写 Base URL")
+	if err := checkAIConfig(cfg); err != nil {
+		return nil, err
 	}
 
 	// 读取简历
@@ -1074,127 +1186,181 @@ func (a *App) AnalyzeResume(resumeID string, cfg *AIConfig, jobCfg *JobConfig) (
 		return nil, fmt.Errorf("解析简历失败: %v", err)
 	}
 
-	// 每次分析前重新提取文件内容（避免使用旧解析器缓存的错误内容）
-	if resume.FilePath != "" && resume.FilePath != resume.FileName {
-		freshContent := a.extractText(resume.FilePath)
-		if freshContent != "" && len(freshContent) > 20 {
+	// 每次分析前重新提取文件内容（避免使用旧解析器缓存的错误内容）；原始文件不存在时使用已有内容
+	if a.adoptOriginal(&resume) {
+		a.saveResume(&resume)
+	}
+	if src := existingResumeFile(&resume); src != "" {
+		freshContent, report, err := a.extractText(ctx, src)
+		if ctx.Err() != nil {
+			return nil, a.abortAnalysis(&resume, ctx.Err())
+		}
+		if err != nil {
+			log.Printf("[AnalyzeResume] 重新提取失败: %s, %v", resume.FileName, err)
+			resume.ExtractError = err.Error()
+		} else if freshContent != "" && len(freshContent) > 20 {
 			log.Printf("[AnalyzeResume] 重新提取内容: %s, 长度=%d", resume.FileName, len(freshContent))
-			resume.Content = freshContent
+			resume.ExtractError = ""
+			setResumeContent(&resume, freshContent, report)
 			a.saveResume(&resume) // 更新磁盘缓存
 		} else {
 			log.Printf("[AnalyzeResume] 重新提取失败或内容过短，使用已有内容")
 		}
 	}
 
+	// 提取质量检查：只有文件名占位内容时拒绝分析，避免给文件名打分
+	if resume.Extraction != nil && resume.Extraction.UsedPlaceholder {
+		msg := "未能提取到简历文本，无法分析"
+		if resume.ExtractError != "" {
+			msg += ": " + resume.ExtractError
+		} else if len(resume.Extraction.Warnings) > 0 {
+			msg += ": " + resume.Extraction.Warnings[0]
+		}
+		resume.Status = "error"
+		a.saveResume(&resume)
+		a.emit("analysis:error", map[string]interface{}{
+			"id":    resumeID,
+			"error": msg,
+		})
+		return nil, fmt.Errorf("%s", msg)
+	}
+
+	// 内容、岗位配置、提示词与模型都没有变化时直接使用上次的结果
+	if !force && !resume.ForceAnalyze {
+		if cached := a.loadCachedAnalysis(analysisCacheHash(resume.FileName, resume.Content, jobCfg, cfg.Model)); cached != nil {
+			log.Printf("[AnalyzeResume] 使用缓存的分析结果: %s", resume.FileName)
+			cached.Cached = true
+			a.completeAnalysis(&resume, cached)
+			return cached, nil
+		}
+	}
+
 	// 更新状态为分析中
 	resume.Status = "analyzing"
 	a.saveResume(&resume)
-	runtime.EventsEmit(a.ctx, "analysis:progress", map[string]interface{}{
+	a.emit("analysis:progress", map[string]interface{}{
 		"id":       resumeID,
 		"status":   "analyzing",
 		"progress": 10,
 	})
 
-	// 构建 Prompt - 进度 30%
-	prompt := a.buildAnalysisPrompt(&resume, jobCfg)
-	runtime.EventsEmit(a.ctx, "analysis:progress", map[string]interface{}{
+	// 构建 Prompt - 进度 20%，之后按流式输出中已生成的字段推进
+	prompt, budget := a.buildAnalysisPrompt(&resume, jobCfg, cfg.Model)
+	a.emit("analysis:progress", map[string]interface{}{
 		"id":       resumeID,
 		"status":   "analyzing",
-		"progress": 30,
+		"progress": analysisPromptProgress,
 	})
 
-	// 调用 AI - 进度 50%
-	runtime.EventsEmit(a.ctx, "analysis:progress", map[string]interface{}{
-		"id":       resumeID,
-		"status":   "analyzing",
-		"progress": 50,
-	})
-	result, err := a.callAI(cfg, prompt)
+	messages := analysisMessages(prompt)
+	usage := &AIUsage{}
+	onStream, flushStream := a.analysisStreamer(resumeID)
+	result, used, err := a.callAI(ctx, cfg, messages, onStream)
+	if ctx.Err() != nil {
+		return nil, a.abortAnalysis(&resume, ctx.Err())
+	}
 	if err != nil {
 		resume.Status = "error"
 		a.saveResume(&resume)
-		runtime.EventsEmit(a.ctx, "analysis:error", map[string]interface{}{
-			"id":    resumeID,
-			"error": err.Error(),
-		})
+		a.emit("analysis:error", analysisErrorEvent(resumeID, err.Error(), err))
 		return nil, err
 	}
+	// 推送节流期间未发出的最后一段回复与最终 token 数
+	flushStream()
 
-	// 解析 AI 返回结果 - 进度 80%
-	runtime.EventsEmit(a.ctx, "analysis:progress", map[string]interface{}{
+	// 解析 AI 返回结果
+	a.emit("analysis:progress", map[string]interface{}{
 		"id":       resumeID,
 		"status":   "analyzing",
-		"progress": 80,
+		"progress": analysisStreamMaxProgress,
 	})
-	analysis, err := a.parseAnalysisResult(result)
+	a.recordUsage(usage, used, result)
+	analysis, err := a.analyzeWithRepair(ctx, used, messages, result.Content, usage)
+	// 已发生的请求无论分析成功与否都计入花费
+	a.chargeResume(&resume, usage)
+	if ctx.Err() != nil {
+		return nil, a.abortAnalysis(&resume, ctx.Err())
+	}
 	if err != nil {
 		resume.Status = "error"
 		a.saveResume(&resume)
-		runtime.EventsEmit(a.ctx, "analysis:error", map[string]interface{}{
-			"id":    resumeID,
-			"error": "解析AI返回失败: " + err.Error(),
-		})
+		a.emit("analysis:error", analysisErrorEvent(resumeID, "解析AI返回失败: "+err.Error(), err))
 		return nil, err
 	}
 
+	analysis.ExtractionWarning = extractionWarning(resume.Extraction)
+	analysis.Prompt = budget
+	analysis.Provider = aiProfileLabel(used)
+	analysis.Model = used.Model
+	analysis.Usage = usage
+	// 与本地解析的联系方式交叉校验：AI 未提取到姓名时补全，不一致时提示
+	if resume.Contact != nil && resume.Contact.Name != "" {
+		if analysis.CandidateName == "" {
+			analysis.CandidateName = resume.Contact.Name
+		} else if !contactNameMatches(resume.Contact.Name, analysis.CandidateName) {
+			analysis.CrossCheckWarnings = append(analysis.CrossCheckWarnings,
+				fmt.Sprintf("AI 提取的姓名「%s」与简历解析的姓名「%s」不一致", analysis.CandidateName, resume.Contact.Name))
+		}
+	}
+	// 按实际使用的模型缓存（切换到备用配置时，主配置的模型仍未分析过）
+	a.saveCachedAnalysis(analysisCacheHash(resume.FileName, resume.Content, jobCfg, used.Model), analysis)
+	a.completeAnalysis(&resume, analysis)
+	return analysis, nil
+}
+
+// completeAnalysis 保存分析结果并发送完成事件
+func (a *App) completeAnalysis(resume *Resume, analysis *AnalysisResult) {
 	// 更新简历状态 - 进度 100%
-	runtime.EventsEmit(a.ctx, "analysis:progress", map[string]interface{}{
-		"id":       resumeID,
+	a.emit("analysis:progress", map[string]interface{}{
+		"id":       resume.ID,
 		"status":   "analyzing",
 		"progress": 100,
 	})
 	resume.Status = "done"
 	resume.Score = int(math.Round(analysis.OverallScore))
 	resume.Analysis = analysis
-	a.saveResume(&resume)
+	resume.ForceAnalyze = false
+	a.saveResume(resume)
 
 	// 发送完成事件
-	runtime.EventsEmit(a.ctx, "analysis:completed", map[string]interface{}{
-		"id":       resumeID,
+	a.emit("analysis:completed", map[string]interface{}{
+		"id":       resume.ID,
 		"score":    analysis.OverallScore,
 		"analysis": analysis,
+		"cached":   analysis.Cached,
 	})
+}
 
-	return analysis, nil
+// abortAnalysis 分析被取消：简历恢复为待分析，以便之后重新分析
+func (a *App) abortAnalysis(resume *Resume, err error) error {
+	log.Printf("[analyzeResume] 分析已取消: %s", resume.FileName)
+	resume.Status = "pending"
+	a.saveResume(resume)
+	a.emit("analysis:progress", map[string]interface{}{
+		"id":       resume.ID,
+		"status":   "pending",
+		"progress": 0,
+	})
+	return err
 }
 
-// StartBatchAnalysis 批量分析简历
-func (a *App) StartBatchAnalysis(resumeIDs []string, cfg *AIConfig, jobCfg *JobConfig) {
-	if cfg == nil || cfg.APIKey == "" {
+// StartBatchAnalysis 批量分析简历，返回任务ID（可用于取消、暂停）
+func (a *App) StartBatchAnalysis(resumeIDs []string, cfg *AIConfig, jobCfg *JobConfig) string {
+	if err := checkAIConfig(cfg); err != nil {
 		log.Println("[StartBatchAnalysis] AI 未配置，终止")
-		runtime.EventsEmit(a.ctx, "analysis:error", map[string]interface{}{
+		a.emit("analysis:error", map[string]interface{}{
 			"id":    "",
-			"error": "AI 未配置: 请先在设置中填写 API Key",
+			"error": err.Error(),
 		})
-		return
+		return ""
 	}
-	go func() {
-		total := len(resumeIDs)
-		for i, id := range resumeIDs {
-			// 发送进度
-			runtime.EventsEmit(a.ctx, "batch:progress", map[string]interface{}{
-				"current":  i + 1,
-				"total":    total,
-				"resumeId": id,
-			})
-
-			_, err := a.AnalyzeResume(id, cfg, jobCfg)
-			if err != nil {
-				log.Printf("分析简历 %s 失败: %v", id, err)
-			}
-
-			// 防止请求过快
-			time.Sleep(500 * time.Millisecond)
-		}
-
-		runtime.EventsEmit(a.ctx, "batch:completed", map[string]interface{}{
-			"total": total,
-		})
-	}()
+	// 并发分析，请求速率由服务商限流器控制；不属于项目的任务不会登记失败
+	job, _ := a.startJob(context.Background(), "", resumeIDs, cfg, jobCfg, nil, nil)
+	return job.info.ID
 }
 
-// buildAnalysisPrompt 构建分析提示词
-func (a *App) buildAnalysisPrompt(resume *Resume, jobCfg *JobConfig) string {
+// renderAnalysisPrompt 用给定的简历内容渲染分析提示词
+func (a *App) renderAnalysisPrompt(fileName string, jobCfg *JobConfig, content string) string {
 	skills := strings.Join(jobCfg.RequiredSkills, "、")
 	requirements := strings.Join(jobCfg.Requirements, "\n- ")
 
@@ -1282,8 +1448,8 @@ func (a *App) buildAnalysisPrompt(resume *Resume, jobCfg *JobConfig) string {
 		jobCfg.EducationLevel,
 		skills,
 		requirements,
-		resume.FileName,
-		a.truncateContent(resume.Content, 10000),
+		fileName,
+		content,
 	)
 
 	// 使用 system + user 消息格式
@@ -1295,97 +1461,46 @@ func (a *App) truncateContent(content string, maxLen int) string {
 	if len(content) <= maxLen {
 		return content
 	}
-	return content[:maxLen] + "\n...(内容已截断)"
+	return truncateRunes(content, maxLen) + "\n...(内容已截断)"
 }
 
-// callAI 调用AI接口
-func (a *App) callAI(cfg *AIConfig, prompt string) (string, error) {
-	// 拆分 system prompt 和 user prompt
+// analysisMessages 拆分 system prompt 和 user prompt
+func analysisMessages(prompt string) []ChatMessage {
 	parts := strings.SplitN(prompt, "\n\n---\n\n", 2)
 	systemMsg := parts[0]
 	userMsg := prompt
 	if len(parts) == 2 {
 		userMsg = parts[1]
 	}
-
-	reqBody := ChatRequest{
-		Model: cfg.Model,
-		Messages: []ChatMessage{
-			{
-				Role:    "system",
-				Content: systemMsg,
-			},
-			{
-				Role:    "user",
-				Content: userMsg,
-			},
+	return []ChatMessage{
+		{
+			Role:    "system",
+			Content: systemMsg,
+		},
+		{
+			Role:    "user",
+			Content: userMsg,
 		},
-		Temperature: 0.2,
-		MaxTokens:   4000,
 	}
+}
 
-	body, _ := json.Marshal(reqBody)
-	url := strings.TrimSuffix(cfg.BaseURL, "/") + "/chat/completions"
-
-	var lastErr error
-	for attempt := 0; attempt < cfg.MaxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(time.Duration(attempt) * 2 * time.Second)
-		}
-
-		req, err := http.NewRequest("POST", url, bytes.NewReader(body))
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-
-		client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		respBody, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-
-		if resp.StatusCode == 429 {
-			lastErr = fmt.Errorf("请求过于频繁")
-			continue
-		}
-
-		if resp.StatusCode >= 400 {
-			lastErr = fmt.Errorf("API错误: %d - %s", resp.StatusCode, string(respBody))
-			continue
-		}
-
-		var chatResp ChatResponse
-		if err := json.Unmarshal(respBody, &chatResp); err != nil {
-			lastErr = fmt.Errorf("解析响应失败: %v", err)
-			continue
-		}
-
-		if chatResp.Error != nil {
-			lastErr = fmt.Errorf("AI错误: %s", chatResp.Error.Message)
-			continue
-		}
-
-		if len(chatResp.Choices) == 0 {
-			lastErr = fmt.Errorf("AI未返回结果")
-			continue
-		}
-
-		return chatResp.Choices[0].Message.Content, nil
+// callAI 调用AI接口，要求按分析结果的 JSON Schema 输出；onStream 非空时流式接收回复。
+// cfg 不可用时切换到备用配置，返回回复、用量与实际使用的配置
+func (a *App) callAI(ctx context.Context, cfg *AIConfig, messages []ChatMessage, onStream func(string, int)) (*ChatResult, *AIConfig, error) {
+	reqBody := &ChatRequest{
+		Model:          cfg.Model,
+		Messages:       messages,
+		Temperature:    analysisTemperature,
+		MaxTokens:      analysisMaxTokens,
+		ResponseFormat: analysisResponseFormat(),
+		OnStream:       onStream,
 	}
 
-	return "", fmt.Errorf("重试%d次后失败: %v", cfg.MaxRetries, lastErr)
+	return a.chatWithFailover(ctx, cfg, reqBody)
 }
 
-// parseAnalysisResult 解析AI返回的分析结果
-func (a *App) parseAnalysisResult(content string) (*AnalysisResult, error) {
+// parseAnalysisResult 解析AI返回的分析结果；problems 为未通过 schema 校验的问题（结果已按规则修正）
+func (a *App) parseAnalysisResult(content string) (*AnalysisResult, []string, error) {
 	// 尝试提取JSON
 	jsonStr := content
 
@@ -1417,8 +1532,9 @@ func (a *App) parseAnalysisResult(content string) (*AnalysisResult, error) {
 
 	var result AnalysisResult
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("JSON解析失败: %v, 内容: %s", err, jsonStr[:min(200, len(jsonStr))])
+		return nil, nil, fmt.Errorf("JSON解析失败: %v, 内容: %s", err, truncateRunes(jsonStr, 200))
 	}
+	problems := validateAnalysisJSON([]byte(jsonStr))
 
 	// 验证并修正数据：四舍五入并限制在 0-100 范围
 	result.OverallScore = clampFloat(math.Round(result.OverallScore), 0, 100)
@@ -1449,7 +1565,7 @@ func (a *App) parseAnalysisResult(content string) (*AnalysisResult, error) {
 
 	result.AnalyzedAt = time.Now().Format(time.RFC3339)
 
-	return &result, nil
+	return &result, problems, nil
 }
 
 func clampFloat(value, minVal, maxVal float64) float64 {
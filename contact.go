@@ -0,0 +1,226 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ============================================
+// 联系方式提取（本地正则/启发式，不消耗 AI）
+// ============================================
+
+// ContactInfo 从简历文本中解析出的候选人联系方式
+type ContactInfo struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Location string `json:"location"`
+	GitHub   string `json:"github"`
+	LinkedIn string `json:"linkedin"`
+}
+
+var (
+	contactEmailRe    = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	contactCNMobileRe = regexp.MustCompile(`(?:\+?86[\s\-]?)?(1[3-9]\d)[\s\-]?(\d{4})[\s\-]?(\d{4})`)
+	contactIntlPhone  = regexp.MustCompile(`\+\d{1,3}[\s\-]?\(?\d{1,4}\)?(?:[\s\-]?\d{2,4}){2,3}`)
+	contactUSPhoneRe  = regexp.MustCompile(`\(?\b\d{3}\)?[\s.\-]\d{3}[\s.\-]\d{4}\b`)
+	contactGitHubRe   = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?github\.com/[A-Za-z0-9\-_]+`)
+	contactLinkedInRe = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z]{2,3}\.)?linkedin\.com/in/[A-Za-z0-9\-_%]+`)
+	contactLabelRe    = regexp.MustCompile(`(?i)^\s*(姓\s*名|name|现居地|居住地|所在地|所在城市|现居城市|地\s*址|城\s*市|location|address|based\s+in)(?:\s*[:：]\s*|\s+)(.+)$`)
+	contactENNameRe   = regexp.MustCompile(`^[A-Z][a-zA-Z'\-]+(?:\s+[A-Z][a-zA-Z'\-.]*){1,2}$`)
+)
+
+// contactCities 没有"现居地"标签时，在联系方式区块中匹配的常见城市
+var contactCities = []string{
+	"北京", "上海", "广州", "深圳", "杭州", "成都", "武汉", "南京", "西安", "苏州", "天津", "重庆",
+	"长沙", "郑州", "厦门", "青岛", "合肥", "东莞", "佛山", "宁波", "珠海", "大连", "沈阳", "济南",
+	"福州", "昆明", "哈尔滨", "长春", "南昌", "贵阳", "南宁", "太原", "石家庄", "无锡", "香港", "台北",
+}
+
+// contactNameStopwords 标题类文本，不能当作姓名
+var contactNameStopwords = map[string]bool{
+	"个人简历": true, "简历": true, "求职简历": true, "个人信息": true, "基本信息": true, "联系方式": true,
+	"本科": true, "硕士": true, "博士": true, "大专": true, "研究生": true, "应届生": true, "汉族": true,
+	"已婚": true, "未婚": true, "党员": true, "中共党员": true, "团员": true, "群众": true,
+	"resume": true, "cv": true, "curriculum vitae": true, "contact": true, "profile": true,
+}
+
+// extractContactInfo 解析联系方式；姓名和城市只在开头的联系方式区块中查找，避免误取公司或项目名称
+func extractContactInfo(content string, sections []ResumeSection, fileName string) *ContactInfo {
+	info := &ContactInfo{}
+
+	header := content
+	for _, sec := range sections {
+		if sec.Kind == SectionContact {
+			header = sec.Content
+			break
+		}
+	}
+	// 没有识别出分节时，取前 15 行作为头部
+	if len(sections) <= 1 {
+		lines := strings.SplitN(content, "\n", 16)
+		if len(lines) > 15 {
+			lines = lines[:15]
+		}
+		header = strings.Join(lines, "\n")
+	}
+
+	info.Email = contactEmailRe.FindString(content)
+	info.Phone = findPhone(content)
+	if m := contactGitHubRe.FindString(content); m != "" {
+		info.GitHub = normalizeProfileURL(m)
+	}
+	if m := contactLinkedInRe.FindString(content); m != "" {
+		info.LinkedIn = normalizeProfileURL(m)
+	}
+
+	for _, line := range strings.Split(header, "\n") {
+		for _, part := range splitContactLine(line) {
+			m := contactLabelRe.FindStringSubmatch(part)
+			if m == nil {
+				continue
+			}
+			label := strings.ToLower(strings.Join(strings.Fields(m[1]), ""))
+			value := strings.TrimSpace(m[2])
+			switch label {
+			case "姓名", "name":
+				if info.Name == "" && looksLikeName(value) {
+					info.Name = value
+				}
+			default:
+				if info.Location == "" && !contactEmailRe.MatchString(value) {
+					info.Location = value
+				}
+			}
+		}
+	}
+
+	if info.Name == "" {
+		info.Name = guessNameFromHeader(header)
+	}
+	if info.Name == "" {
+		info.Name = guessNameFromFileName(fileName)
+	}
+	if info.Location == "" {
+		for _, city := range contactCities {
+			if strings.Contains(header, city) {
+				info.Location = city
+				break
+			}
+		}
+	}
+	return info
+}
+
+// splitContactLine 头部常见"张三 | 138xxxx | a@b.com"的写法，按分隔符拆开
+func splitContactLine(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool {
+		return r == '|' || r == '｜' || r == '/' || r == '•' || r == '\t'
+	})
+}
+
+// findPhone 优先匹配中国大陆手机号，其次国际号码与北美格式
+func findPhone(content string) string {
+	if m := contactCNMobileRe.FindStringSubmatch(content); m != nil {
+		return m[1] + m[2] + m[3]
+	}
+	if m := contactIntlPhone.FindString(content); m != "" {
+		return strings.TrimSpace(m)
+	}
+	if m := contactUSPhoneRe.FindString(content); m != "" {
+		return strings.TrimSpace(m)
+	}
+	return ""
+}
+
+// normalizeProfileURL 统一为 https:// 开头的小写域名链接
+func normalizeProfileURL(u string) string {
+	lower := strings.ToLower(u)
+	lower = strings.TrimPrefix(lower, "https://")
+	lower = strings.TrimPrefix(lower, "http://")
+	lower = strings.TrimPrefix(lower, "www.")
+	// 保留路径部分原有大小写
+	path := u[len(u)-len(lower):]
+	if i := strings.Index(lower, "/"); i != -1 {
+		return "https://" + lower[:i] + path[i:]
+	}
+	return "https://" + lower
+}
+
+// guessNameFromHeader 在头部前几行中找像姓名的短文本（2-4 个汉字或 2-3 个首字母大写的英文单词）
+func guessNameFromHeader(header string) string {
+	checked := 0
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if checked++; checked > 5 {
+			break
+		}
+		for _, part := range splitContactLine(line) {
+			part = strings.TrimSpace(part)
+			if looksLikeName(part) {
+				return part
+			}
+		}
+	}
+	return ""
+}
+
+// guessNameFromFileName 文件名常见"张三_Go开发_5年.pdf"的写法
+func guessNameFromFileName(fileName string) string {
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	for _, part := range strings.FieldsFunc(base, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '—' || r == '(' || r == ')' || r == '（' || r == '）' || r == '【' || r == '】'
+	}) {
+		if isChineseName(part) && !contactNameStopwords[part] {
+			return part
+		}
+	}
+	return ""
+}
+
+// looksLikeName 判断一段文本是否像姓名
+func looksLikeName(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" || contactNameStopwords[strings.ToLower(s)] {
+		return false
+	}
+	for _, city := range contactCities {
+		if s == city {
+			return false
+		}
+	}
+	if isChineseName(s) {
+		return true
+	}
+	return contactENNameRe.MatchString(s) && utf8.RuneCountInString(s) <= 40
+}
+
+// isChineseName 2-4 个汉字（允许少数民族姓名中的间隔点）
+func isChineseName(s string) bool {
+	n := 0
+	for _, r := range s {
+		if r == '·' {
+			continue
+		}
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+		n++
+	}
+	return n >= 2 && n <= 4
+}
+
+// contactNameMatches 比较本地解析的姓名与 AI 提取的姓名（忽略空白与大小写）
+func contactNameMatches(local, ai string) bool {
+	norm := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	l, r := norm(local), norm(ai)
+	return l == "" || r == "" || strings.Contains(r, l) || strings.Contains(l, r)
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================
+// 重复简历检测（文件哈希 + 归一化文本指纹 + SimHash）
+// ============================================
+
+// 重复简历的处理方式
+const (
+	DuplicateAsk     = "ask"     // 标记为待确认，由用户通过 ResolveDuplicate 选择
+	DuplicateSkip    = "skip"    // 不导入新文件
+	DuplicateReplace = "replace" // 新文件替换同一项目中的旧记录
+	DuplicateLink    = "link"    // 保留新记录并关联到旧记录（默认）
+)
+
+// simHashNearDistance SimHash 汉明距离不超过该值视为同一份简历的修改版
+const simHashNearDistance = 6
+
+// simHashMinRunes 文本过短时 SimHash 不可靠，不参与近似判断
+const simHashMinRunes = 200
+
+// ImportConfig 导入配置
+type ImportConfig struct {
+	DuplicatePolicy string `json:"duplicate_policy"` // ask / skip / replace / link
+}
+
+// DuplicateMatch 与已有简历的重复关系
+type DuplicateMatch struct {
+	ResumeID  string `json:"resume_id"`  // 已有简历 ID
+	ProjectID string `json:"project_id"` // 已有简历所在项目
+	FileName  string `json:"file_name"`
+	Kind      string `json:"kind"`   // exact（文件或文本完全相同）/ near（同一候选人的更新版本）
+	Reason    string `json:"reason"` // 判定依据
+}
+
+// fileSHA256 计算文件内容的 SHA-256，文件不可读时返回空
+func fileSHA256(filePath string) string {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeForFingerprint 去掉空白与标点并转小写，使同一文本在不同格式/换行方式下得到相同指纹
+func normalizeForFingerprint(content string) string {
+	var sb strings.Builder
+	for _, r := range content {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return sb.String()
+}
+
+// textFingerprint 归一化文本的 SHA-256
+func textFingerprint(normalized string) string {
+	if normalized == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// textSimHash 以 3 字符片段为特征计算 64 位 SimHash，用于发现内容大部分相同的简历
+func textSimHash(normalized string) uint64 {
+	runes := []rune(normalized)
+	if len(runes) < simHashMinRunes {
+		return 0
+	}
+	var weights [64]int
+	for i := 0; i+3 <= len(runes); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[i : i+3])))
+		v := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if v&(1<<uint(b)) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+	var out uint64
+	for b := 0; b < 64; b++ {
+		if weights[b] > 0 {
+			out |= 1 << uint(b)
+		}
+	}
+	return out
+}
+
+// setResumeFingerprints 计算并写入文件哈希与文本指纹；占位内容不计算文本指纹
+func setResumeFingerprints(r *Resume) {
+	r.FileHash = fileSHA256(r.FilePath)
+	r.TextFingerprint, r.SimHash = "", ""
+	if r.Extraction != nil && r.Extraction.UsedPlaceholder {
+		return
+	}
+	normalized := normalizeForFingerprint(r.Content)
+	r.TextFingerprint = textFingerprint(normalized)
+	if sh := textSimHash(normalized); sh != 0 {
+		r.SimHash = strconv.FormatUint(sh, 16)
+	}
+}
+
+// duplicateIndex 查重用的已有简历快照：批量导入时只读取一次磁盘，之后导入的简历随时加入，
+// 文件哈希与文本指纹按键直接查找，只有近似判断需要逐个比较
+type duplicateIndex struct {
+	resumes []*Resume
+	exact   map[string][]*Resume // "file:<哈希>" / "text:<指纹>"
+}
+
+// newDuplicateIndex 读取所有简历建立索引
+func (a *App) newDuplicateIndex() *duplicateIndex {
+	idx := &duplicateIndex{exact: map[string][]*Resume{}}
+	for _, r := range a.GetResumes() {
+		idx.add(r)
+	}
+	return idx
+}
+
+// add 加入一份简历；待确认的重复简历不作为比较对象
+func (idx *duplicateIndex) add(r *Resume) {
+	if r.Status == "duplicate" {
+		return
+	}
+	idx.resumes = append(idx.resumes, r)
+	for _, key := range exactKeys(r) {
+		idx.exact[key] = append(idx.exact[key], r)
+	}
+}
+
+// remove 移除已删除的简历
+func (idx *duplicateIndex) remove(id string) {
+	drop := func(list []*Resume) []*Resume {
+		kept := list[:0]
+		for _, r := range list {
+			if r.ID != id {
+				kept = append(kept, r)
+			}
+		}
+		return kept
+	}
+	idx.resumes = drop(idx.resumes)
+	for key, list := range idx.exact {
+		idx.exact[key] = drop(list)
+	}
+}
+
+// exactKeys 完全相同判定使用的键
+func exactKeys(r *Resume) []string {
+	var keys []string
+	if r.FileHash != "" {
+		keys = append(keys, "file:"+r.FileHash)
+	}
+	if r.TextFingerprint != "" {
+		keys = append(keys, "text:"+r.TextFingerprint)
+	}
+	return keys
+}
+
+// findDuplicate 在所有项目中查找与 r 重复的简历：完全相同优先于近似，同一项目优先于其他项目
+func (idx *duplicateIndex) findDuplicate(r *Resume) *DuplicateMatch {
+	var candidates []*Resume
+	for _, key := range exactKeys(r) {
+		candidates = append(candidates, idx.exact[key]...)
+	}
+	if len(candidates) == 0 {
+		candidates = idx.resumes
+	}
+
+	var best *DuplicateMatch
+	bestRank := 0
+	for _, other := range candidates {
+		if other.ID == r.ID {
+			continue
+		}
+		kind, reason := compareResumes(r, other)
+		if kind == "" {
+			continue
+		}
+		rank := 1
+		if kind == "exact" {
+			rank += 2
+		}
+		if other.ProjectID == r.ProjectID {
+			rank++
+		}
+		if rank > bestRank {
+			bestRank = rank
+			best = &DuplicateMatch{
+				ResumeID:  other.ID,
+				ProjectID: other.ProjectID,
+				FileName:  other.FileName,
+				Kind:      kind,
+				Reason:    reason,
+			}
+		}
+	}
+	return best
+}
+
+// compareResumes 判断两份简历是否重复，返回类型与依据
+func compareResumes(r, other *Resume) (kind string, reason string) {
+	switch {
+	case r.FileHash != "" && r.FileHash == other.FileHash:
+		return "exact", "文件内容完全相同"
+	case r.TextFingerprint != "" && r.TextFingerprint == other.TextFingerprint:
+		return "exact", "简历文本完全相同"
+	}
+
+	if r.Contact != nil && other.Contact != nil {
+		if r.Contact.Email != "" && strings.EqualFold(r.Contact.Email, other.Contact.Email) {
+			return "near", "邮箱相同: " + r.Contact.Email
+		}
+		if p := phoneDigits(r.Contact.Phone); len(p) >= 7 && p == phoneDigits(other.Contact.Phone) {
+			return "near", "电话相同: " + r.Contact.Phone
+		}
+	}
+
+	if r.SimHash != "" && other.SimHash != "" {
+		a, errA := strconv.ParseUint(r.SimHash, 16, 64)
+		b, errB := strconv.ParseUint(other.SimHash, 16, 64)
+		if errA == nil && errB == nil {
+			if d := bits.OnesCount64(a ^ b); d <= simHashNearDistance {
+				return "near", fmt.Sprintf("简历内容高度相似（差异 %d/64）", d)
+			}
+		}
+	}
+	return "", ""
+}
+
+// phoneDigits 只保留电话号码中的数字，去掉 86 国家码
+func phoneDigits(phone string) string {
+	var sb strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	d := sb.String()
+	if len(d) == 13 && strings.HasPrefix(d, "86") {
+		d = d[2:]
+	}
+	return d
+}
+
+// duplicatePolicy 当前配置的重复处理方式；未配置时关联到旧记录，ask 需要前端确认界面，只在明确配置时使用
+func (a *App) duplicatePolicy() string {
+	switch policy := a.currentConfig().Import.DuplicatePolicy; policy {
+	case DuplicateAsk, DuplicateSkip, DuplicateReplace:
+		return policy
+	}
+	return DuplicateLink
+}
+
+// dedupeResume 导入时按配置处理重复（指纹已由 setResumeContent 计算）；返回 false 表示新简历不应保存
+func (a *App) dedupeResume(r *Resume, dups *duplicateIndex) bool {
+	match := dups.findDuplicate(r)
+	if match == nil {
+		return true
+	}
+	log.Printf("[dedupeResume] %s 与 %s 重复（%s）: %s", r.FileName, match.FileName, match.Kind, match.Reason)
+	r.Duplicate = match
+
+	switch a.duplicatePolicy() {
+	case DuplicateSkip:
+		return false
+	case DuplicateReplace:
+		a.replaceWithDuplicate(r)
+		if match.ProjectID == r.ProjectID {
+			dups.remove(match.ResumeID)
+		}
+	case DuplicateLink:
+		a.linkDuplicate(r)
+	default:
+		// 等待用户选择，确认前不参与批量分析
+		r.Status = "duplicate"
+	}
+	return true
+}
+
+// notifyDuplicate 新简历处于待确认状态时通知前端
+func (a *App) notifyDuplicate(r *Resume) {
+	if r.Status != "duplicate" || r.Duplicate == nil {
+		return
+	}
+	a.emit("resume:duplicate", map[string]interface{}{
+		"id":          r.ID,
+		"projectId":   r.ProjectID,
+		"fileName":    r.FileName,
+		"duplicateOf": r.Duplicate,
+	})
+}
+
+// ResolveDuplicate 用户对待确认的重复简历做出选择：skip / replace / link
+func (a *App) ResolveDuplicate(resumeID string, action string) error {
+	r := a.loadResume(resumeID)
+	if r == nil {
+		return fmt.Errorf("简历不存在")
+	}
+	if r.Duplicate == nil {
+		return fmt.Errorf("该简历没有重复记录")
+	}
+
+	switch action {
+	case DuplicateSkip:
+		a.removeResumeFromProject(r.ProjectID, r.ID)
+		return a.DeleteResume(r.ID)
+	case DuplicateReplace:
+		a.replaceWithDuplicate(r)
+	case DuplicateLink:
+		a.linkDuplicate(r)
+	default:
+		return fmt.Errorf("未知的处理方式: %s", action)
+	}
+	a.saveResume(r)
+	a.emit("resume:updated", r)
+	return nil
+}
+
+// replaceWithDuplicate 新简历取代旧记录：旧记录在同一项目中时删除，其他项目中的记录不受影响
+func (a *App) replaceWithDuplicate(r *Resume) {
+	old := r.Duplicate
+	if old.ProjectID == r.ProjectID {
+		a.removeResumeFromProject(old.ProjectID, old.ResumeID)
+		a.DeleteResume(old.ResumeID)
+		log.Printf("[replaceWithDuplicate] %s 已替换 %s", r.FileName, old.FileName)
+	}
+	r.Duplicate = nil
+	r.Status = "pending"
+}
+
+// linkDuplicate 保留新记录并关联到旧记录；文件或文本完全相同且旧记录已按相同岗位要求分析过时直接复用分析结果，避免重复付费
+func (a *App) linkDuplicate(r *Resume) {
+	r.Status = "pending"
+	if r.Duplicate.Kind != "exact" {
+		return
+	}
+	old := a.loadResume(r.Duplicate.ResumeID)
+	if old == nil || old.Status != "done" || old.Analysis == nil {
+		return
+	}
+	// 评分针对岗位要求，其他项目的岗位不同时需要重新分析
+	if old.ProjectID != r.ProjectID {
+		oldJob, newJob := a.projectJobConfig(old.ProjectID), a.projectJobConfig(r.ProjectID)
+		if oldJob == nil || newJob == nil || !reflect.DeepEqual(*oldJob, *newJob) {
+			return
+		}
+	}
+	r.Analysis = old.Analysis
+	r.Score = old.Score
+	r.Status = "done"
+}
+
+// projectJobConfig 项目的岗位配置；未归属项目的简历使用全局配置，项目不存在时返回 nil
+func (a *App) projectJobConfig(projectID string) *JobConfig {
+	if projectID == "" {
+		job := a.currentConfig().Job
+		return &job
+	}
+	if p := a.GetProject(projectID); p != nil {
+		return &p.JobConfig
+	}
+	return nil
+}
+
+// loadResume 读取单份简历，不存在时返回 nil
+func (a *App) loadResume(id string) *Resume {
+	data, err := os.ReadFile(filepath.Join(a.getDataDir(), "resumes", id+".json"))
+	if err != nil {
+		return nil
+	}
+	var r Resume
+	if json.Unmarshal(data, &r) != nil {
+		return nil
+	}
+	return &r
+}
+
+// removeResumeFromProject 从项目的简历列表中移除
+func (a *App) removeResumeFromProject(projectID, resumeID string) {
+	if projectID == "" {
+		return
+	}
+	a.modifyProject(projectID, func(p *Project) {
+		ids := p.ResumeIDs[:0]
+		for _, id := range p.ResumeIDs {
+			if id != resumeID {
+				ids = append(ids, id)
+			}
+		}
+		p.ResumeIDs = ids
+	})
+}
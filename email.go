@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ============================================
+// 邮件导入：.eml 与 mbox，提取附件简历，没有附件时以正文作为简历内容
+// ============================================
+
+// maxEmailBodyBytes 保存到简历元数据中的正文长度上限
+const maxEmailBodyBytes = 5000
+
+// EmailSource 简历来源邮件的元数据
+type EmailSource struct {
+	From      string    `json:"from"`       // 发件人邮箱
+	FromName  string    `json:"from_name"`  // 发件人显示名
+	Subject   string    `json:"subject"`    // 邮件主题
+	Date      time.Time `json:"date"`       // 发送时间
+	MessageID string    `json:"message_id"` // Message-ID
+	Body      string    `json:"body"`       // 正文（截断）
+}
+
+// emailAttachment 邮件中的附件
+type emailAttachment struct {
+	fileName  string
+	mediaType string
+	inline    bool // Content-Disposition: inline 或带 Content-ID（正文中引用的图片，如签名、logo）
+	data      []byte
+}
+
+// parsedEmail 解析后的邮件
+type parsedEmail struct {
+	source      EmailSource
+	text        string // 纯文本正文（没有 text/plain 时由 HTML 转换）
+	attachments []emailAttachment
+}
+
+// emailWordDecoder 解码 RFC 2047 编码的主题、发件人、附件名（支持 GBK/GB2312/Big5 等字符集）
+var emailWordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// charsetReader 把指定字符集的内容转换为 UTF-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "gb2312", "gbk", "x-gbk":
+		// 很多客户端声明 gb2312 实际使用 GBK 字符，统一按 GB18030 解码
+		charset = "gb18030"
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("不支持的字符集: %s", charset)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// decodeHeader 解码邮件头中的编码字
+func decodeHeader(s string) string {
+	if out, err := emailWordDecoder.DecodeHeader(s); err == nil {
+		return out
+	}
+	return s
+}
+
+// parseEmail 解析一封 RFC 822 邮件
+func parseEmail(r io.Reader) (*parsedEmail, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("邮件格式错误: %v", err)
+	}
+
+	pe := &parsedEmail{}
+	pe.source.Subject = decodeHeader(msg.Header.Get("Subject"))
+	pe.source.MessageID = strings.Trim(msg.Header.Get("Message-Id"), "<> ")
+	if d, err := msg.Header.Date(); err == nil {
+		pe.source.Date = d
+	}
+	addrParser := &mail.AddressParser{WordDecoder: emailWordDecoder}
+	if from, err := addrParser.Parse(msg.Header.Get("From")); err == nil {
+		pe.source.From = from.Address
+		pe.source.FromName = from.Name
+	} else {
+		pe.source.From = decodeHeader(msg.Header.Get("From"))
+	}
+
+	var plain, htmlText []string
+	err = walkEmailPart(map[string][]string(msg.Header), msg.Body, 0, func(mediaType string, params map[string]string, fileName string, inline bool, data []byte) {
+		if fileName != "" {
+			pe.attachments = append(pe.attachments, emailAttachment{fileName: fileName, mediaType: mediaType, inline: inline, data: data})
+			return
+		}
+		switch mediaType {
+		case "text/plain":
+			plain = append(plain, decodeCharset(data, params["charset"]))
+		case "text/html":
+			htmlText = append(htmlText, htmlToText(decodeCharset(data, params["charset"])))
+		}
+	})
+	if err != nil {
+		log.Printf("[parseEmail] 解析 MIME 部分失败: %v", err)
+	}
+
+	if len(plain) > 0 {
+		pe.text = strings.TrimSpace(strings.Join(plain, "\n"))
+	} else {
+		pe.text = strings.TrimSpace(strings.Join(htmlText, "\n"))
+	}
+	pe.source.Body = truncateRunes(pe.text, maxEmailBodyBytes)
+	return pe, nil
+}
+
+// walkEmailPart 递归遍历 MIME 结构，对每个叶子部分回调（已完成传输编码解码）；
+// inline 表示该部分嵌入在正文中显示（Content-Disposition: inline 或带 Content-ID）
+func walkEmailPart(header map[string][]string, body io.Reader, depth int,
+	visit func(mediaType string, params map[string]string, fileName string, inline bool, data []byte)) error {
+	get := func(key string) string {
+		if v := header[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	mediaType, params, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") && depth < 10 {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := walkEmailPart(part.Header, part, depth+1, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(body, maxImportFileBytes+1))
+	if err != nil {
+		return err
+	}
+	data := decodeTransferEncoding(raw, get("Content-Transfer-Encoding"))
+
+	fileName := ""
+	disposition, dparams, err := mime.ParseMediaType(get("Content-Disposition"))
+	if err == nil && dparams["filename"] != "" {
+		fileName = dparams["filename"]
+	} else if params["name"] != "" {
+		fileName = params["name"]
+	}
+	inline := disposition == "inline" || get("Content-Id") != ""
+	if fileName != "" {
+		fileName = filepath.Base(decodeHeader(fileName))
+	}
+	// 转发的邮件作为附件时按普通附件处理
+	if mediaType == "message/rfc822" && fileName == "" {
+		fileName = "forwarded.eml"
+	}
+	visit(mediaType, params, fileName, inline, data)
+	return nil
+}
+
+// isResumeAttachment 附件是否可能是简历：跳过正文内嵌的部分与图片（签名、logo 等），
+// 其余按扩展名判断是否为可导入的格式
+func (a *App) isResumeAttachment(att emailAttachment) bool {
+	if att.inline || strings.HasPrefix(att.mediaType, "image/") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(att.fileName))
+	if strings.HasPrefix(mime.TypeByExtension(ext), "image/") {
+		return false
+	}
+	return a.isImportableExt(ext)
+}
+
+// decodeTransferEncoding 处理 base64 / quoted-printable 传输编码
+func decodeTransferEncoding(raw []byte, encoding string) []byte {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		clean := bytes.Map(func(r rune) rune {
+			if r == '\r' || r == '\n' || r == ' ' || r == '\t' {
+				return -1
+			}
+			return r
+		}, raw)
+		out := make([]byte, base64.StdEncoding.DecodedLen(len(clean)))
+		n, err := base64.StdEncoding.Decode(out, clean)
+		if err != nil && n == 0 {
+			return raw
+		}
+		return out[:n]
+	case "quoted-printable":
+		if out, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(raw))); err == nil {
+			return out
+		}
+	}
+	return raw
+}
+
+// decodeCharset 按声明的字符集把正文转为 UTF-8
+func decodeCharset(data []byte, charset string) string {
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return string(data)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return string(data)
+	}
+	return string(out)
+}
+
+// extractFromEmail .eml 文件的简历内容为邮件正文（附件作为独立简历导入）
+func (a *App) extractFromEmail(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", nil
+	}
+	defer f.Close()
+	pe, err := parseEmail(f)
+	if err != nil {
+		return "", err
+	}
+	text := pe.text
+	if pe.source.Subject != "" {
+		text = "邮件主题: " + pe.source.Subject + "\n" + text
+	}
+	return limitContent(strings.TrimSpace(text)), nil
+}
+
+// importEmail 导入一封邮件：有简历附件时逐个导入附件，否则以邮件正文作为简历；均附带邮件元数据
+func (a *App) importEmail(projectID, emlPath, displayPath string, depth int, summary *ImportSummary) {
+	f, err := os.Open(emlPath)
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: "无法读取: " + err.Error()})
+		return
+	}
+	pe, err := parseEmail(f)
+	f.Close()
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: err.Error()})
+		return
+	}
+
+	tmpDir, err := os.MkdirTemp("", "talentlens-email-")
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: "创建临时目录失败: " + err.Error()})
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	found := 0
+	for i, att := range pe.attachments {
+		// 签名图片、日历邀请等非简历附件不计入汇总
+		if !a.isResumeAttachment(att) {
+			continue
+		}
+		found++
+		attPath := displayPath + "!/" + att.fileName
+		dst := filepath.Join(tmpDir, fmt.Sprintf("%d", i), att.fileName)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err == nil {
+			err = os.WriteFile(dst, att.data, 0644)
+		}
+		if err != nil {
+			summary.add(ImportItem{Path: attPath, FileName: att.fileName, Status: ImportFailed, Reason: "保存附件失败: " + err.Error()})
+			continue
+		}
+
+		before := len(summary.Items)
+		a.importAny(projectID, dst, attPath, depth+1, summary)
+		for _, item := range summary.Items[before:] {
+			if item.Status == ImportAccepted {
+				a.attachEmailSource(item.ResumeID, &pe.source)
+			}
+		}
+	}
+	if found > 0 {
+		return
+	}
+
+	// 没有可用的附件，使用邮件正文
+	if strings.TrimSpace(pe.text) == "" {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportSkipped, Reason: "邮件没有简历附件且正文为空"})
+		return
+	}
+	item := a.importFile(projectID, emlPath, displayPath, summary.duplicates(a))
+	if item.Status == ImportAccepted {
+		a.attachEmailSource(item.ResumeID, &pe.source)
+	}
+	summary.add(item)
+}
+
+// attachEmailSource 把邮件元数据写入简历；本地没有解析出姓名和邮箱时用发件人信息补全
+func (a *App) attachEmailSource(resumeID string, src *EmailSource) {
+	r := a.loadResume(resumeID)
+	if r == nil {
+		return
+	}
+	email := *src
+	r.Email = &email
+	if r.Contact == nil {
+		r.Contact = &ContactInfo{}
+	}
+	if r.Contact.Email == "" {
+		r.Contact.Email = src.From
+	}
+	if r.Contact.Name == "" && looksLikeName(src.FromName) {
+		r.Contact.Name = src.FromName
+	}
+	a.saveResume(r)
+}
+
+// importMbox 逐封拆分 mbox 文件，每封邮件写成临时 .eml 后导入
+func (a *App) importMbox(projectID, mboxPath, displayPath string, depth int, summary *ImportSummary) {
+	f, err := os.Open(mboxPath)
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: "无法读取: " + err.Error()})
+		return
+	}
+	defer f.Close()
+
+	tmpDir, err := os.MkdirTemp("", "talentlens-mbox-")
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: "创建临时目录失败: " + err.Error()})
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	count := 0
+	var buf bytes.Buffer
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		count++
+		data := append([]byte(nil), buf.Bytes()...)
+		buf.Reset()
+
+		name := mboxMessageName(data, count)
+		msgPath := filepath.Join(tmpDir, fmt.Sprintf("%d", count), name)
+		display := fmt.Sprintf("%s!/%d %s", displayPath, count, name)
+		if err := os.MkdirAll(filepath.Dir(msgPath), 0755); err == nil {
+			err = os.WriteFile(msgPath, data, 0644)
+		}
+		if err != nil {
+			summary.add(ImportItem{Path: display, FileName: name, Status: ImportFailed, Reason: "保存邮件失败: " + err.Error()})
+			return
+		}
+		a.importEmail(projectID, msgPath, display, depth, summary)
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			switch {
+			case bytes.HasPrefix(line, []byte("From ")):
+				// 分隔行：开始新邮件
+				flush()
+			case bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From ")):
+				// mboxrd 转义：">From " -> "From "
+				buf.Write(line[1:])
+			default:
+				buf.Write(line)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	flush()
+	log.Printf("[importMbox] %s 共 %d 封邮件", filepath.Base(mboxPath), count)
+}
+
+// mboxMessageName 用邮件主题生成临时 .eml 文件名
+func mboxMessageName(data []byte, n int) string {
+	name := fmt.Sprintf("邮件%d", n)
+	if msg, err := mail.ReadMessage(bytes.NewReader(data)); err == nil {
+		if subject := decodeHeader(msg.Header.Get("Subject")); subject != "" {
+			name = subject
+		}
+	}
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+	return truncateRunes(strings.TrimSpace(name), 120) + ".eml"
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// ============================================
+// DOC (Word 97-2003 二进制格式) 文本提取
+// ============================================
+
+// cp1252High 压缩片段中 0x80-0x9F 的 Windows-1252 映射，其余字节与 Latin-1 相同
+var cp1252High = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// extractFromDOC 解析 CFB/OLE 容器中的 WordDocument 流，通过 Clx 片段表还原文本
+// 加密或损坏的文件返回明确的错误，供简历记录提示用户
+func (a *App) extractFromDOC(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("打开文件失败: %v", err)
+	}
+	defer f.Close()
+
+	doc, err := mscfb.New(f)
+	if err != nil {
+		return "", fmt.Errorf("不是有效的 Word 97-2003 文档或文件已损坏: %v", err)
+	}
+
+	streams := map[string][]byte{}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		switch entry.Name {
+		case "WordDocument", "0Table", "1Table":
+			data, err := io.ReadAll(entry)
+			if err != nil {
+				return "", fmt.Errorf("读取 %s 流失败: %v", entry.Name, err)
+			}
+			streams[entry.Name] = data
+		case "EncryptedPackage":
+			// 加密的 .docx 同样以 CFB 容器保存
+			return "", fmt.Errorf("文档已加密，请移除密码后重新导入")
+		}
+	}
+
+	wordDoc, ok := streams["WordDocument"]
+	if !ok {
+		return "", fmt.Errorf("缺少 WordDocument 流，文件不是 Word 文档或已损坏")
+	}
+
+	text, err := docPieceText(wordDoc, streams)
+	if err != nil {
+		return "", err
+	}
+
+	result := docCleanText(text)
+	if result == "" {
+		log.Printf("[extractFromDOC] DOC 提取结果为空: %s", filePath)
+		return "", nil
+	}
+
+	log.Printf("[extractFromDOC] 提取成功: %s, 长度=%d 字符", filepath.Base(filePath), len(result))
+	result = limitContent(result)
+	return result, nil
+}
+
+// docPieceText 读取 FIB，定位表流中的片段表（PlcPcd）并拼接所有片段文本
+func docPieceText(wordDoc []byte, streams map[string][]byte) (string, error) {
+	if len(wordDoc) < 32 || binary.LittleEndian.Uint16(wordDoc[0:]) != 0xA5EC {
+		return "", fmt.Errorf("WordDocument 流标识无效，文件已损坏")
+	}
+	if nFib := binary.LittleEndian.Uint16(wordDoc[2:]); nFib < 0x00C1 {
+		return "", fmt.Errorf("不支持 Word 97 之前的文档格式 (nFib=0x%X)", nFib)
+	}
+
+	flags := binary.LittleEndian.Uint16(wordDoc[0x0A:])
+	if flags&0x0100 != 0 {
+		return "", fmt.Errorf("文档已加密，请移除密码后重新导入")
+	}
+	tableName := "0Table"
+	if flags&0x0200 != 0 {
+		tableName = "1Table"
+	}
+	table, ok := streams[tableName]
+	if !ok {
+		return "", fmt.Errorf("缺少 %s 流，文件已损坏", tableName)
+	}
+
+	// FIB 变长部分: csw + fibRgW, cslw + fibRgLw, cbRgFcLcb + fibRgFcLcbBlob
+	pos := 32
+	readU16 := func() (int, error) {
+		if pos+2 > len(wordDoc) {
+			return 0, fmt.Errorf("FIB 结构被截断，文件已损坏")
+		}
+		v := int(binary.LittleEndian.Uint16(wordDoc[pos:]))
+		pos += 2
+		return v, nil
+	}
+	csw, err := readU16()
+	if err != nil {
+		return "", err
+	}
+	pos += csw * 2
+	cslw, err := readU16()
+	if err != nil {
+		return "", err
+	}
+	rgLw := pos
+	pos += cslw * 4
+	if _, err := readU16(); err != nil {
+		return "", err
+	}
+	rgFcLcb := pos
+
+	// fibRgLw97: ccpText, ccpFtn, ccpHdd, (保留), ccpAtn, ccpEdn, ccpTxbx, ccpHdrTxbx
+	totalCP := 0
+	if cslw >= 11 && rgLw+11*4 <= len(wordDoc) {
+		for _, idx := range []int{3, 4, 5, 7, 8, 9, 10} {
+			totalCP += int(int32(binary.LittleEndian.Uint32(wordDoc[rgLw+idx*4:])))
+		}
+	}
+
+	// fcClx/lcbClx 位于 FibRgFcLcb97 的第 33 对
+	clxOff := rgFcLcb + 33*8
+	if clxOff+8 > len(wordDoc) {
+		return "", fmt.Errorf("FIB 缺少片段表位置，文件已损坏")
+	}
+	fcClx := int(binary.LittleEndian.Uint32(wordDoc[clxOff:]))
+	lcbClx := int(binary.LittleEndian.Uint32(wordDoc[clxOff+4:]))
+	if lcbClx <= 0 || fcClx < 0 || fcClx+lcbClx > len(table) {
+		return "", fmt.Errorf("片段表位置越界，文件已损坏")
+	}
+	clx := table[fcClx : fcClx+lcbClx]
+
+	// 跳过 Prc（0x01），找到 Pcdt（0x02）
+	for len(clx) > 0 && clx[0] == 0x01 {
+		if len(clx) < 3 {
+			return "", fmt.Errorf("片段表结构被截断，文件已损坏")
+		}
+		cb := int(int16(binary.LittleEndian.Uint16(clx[1:])))
+		if cb < 0 || 3+cb > len(clx) {
+			return "", fmt.Errorf("片段表结构被截断，文件已损坏")
+		}
+		clx = clx[3+cb:]
+	}
+	if len(clx) < 5 || clx[0] != 0x02 {
+		return "", fmt.Errorf("未找到片段表，文件已损坏")
+	}
+	lcb := int(binary.LittleEndian.Uint32(clx[1:]))
+	plc := clx[5:]
+	if lcb < 4 || lcb > len(plc) || (lcb-4)%12 != 0 {
+		return "", fmt.Errorf("片段表长度无效，文件已损坏")
+	}
+
+	n := (lcb - 4) / 12
+	pcdBase := (n + 1) * 4
+	var sb strings.Builder
+	emitted := 0
+	for i := 0; i < n; i++ {
+		cpStart := int(binary.LittleEndian.Uint32(plc[i*4:]))
+		cpEnd := int(binary.LittleEndian.Uint32(plc[(i+1)*4:]))
+		count := cpEnd - cpStart
+		if count <= 0 {
+			continue
+		}
+		if totalCP > 0 {
+			if emitted >= totalCP {
+				break
+			}
+			if emitted+count > totalCP {
+				count = totalCP - emitted
+			}
+		}
+
+		fc := binary.LittleEndian.Uint32(plc[pcdBase+i*8+2:])
+		compressed := fc&0x40000000 != 0
+		offset := int(fc & 0x3FFFFFFF)
+		if compressed {
+			offset /= 2
+			if offset+count > len(wordDoc) {
+				return "", fmt.Errorf("文本片段越界，文件已损坏")
+			}
+			for _, b := range wordDoc[offset : offset+count] {
+				if b >= 0x80 && b <= 0x9F {
+					sb.WriteRune(cp1252High[b-0x80])
+				} else {
+					sb.WriteRune(rune(b))
+				}
+			}
+		} else {
+			if offset+count*2 > len(wordDoc) {
+				return "", fmt.Errorf("文本片段越界，文件已损坏")
+			}
+			units := make([]uint16, count)
+			for j := range units {
+				units[j] = binary.LittleEndian.Uint16(wordDoc[offset+j*2:])
+			}
+			sb.WriteString(string(utf16.Decode(units)))
+		}
+		emitted += count
+	}
+
+	return sb.String(), nil
+}
+
+// docCleanText 处理 Word 控制字符：段落/换行/单元格标记、域代码、对象占位符
+func docCleanText(raw string) string {
+	var sb strings.Builder
+	fieldDepth := 0 // 域开始(0x13)到域分隔(0x14)之间是域指令，不输出
+	inInstr := []bool{}
+	for _, r := range raw {
+		switch r {
+		case 0x13:
+			fieldDepth++
+			inInstr = append(inInstr, true)
+			continue
+		case 0x14:
+			if fieldDepth > 0 {
+				inInstr[fieldDepth-1] = false
+			}
+			continue
+		case 0x15:
+			if fieldDepth > 0 {
+				fieldDepth--
+				inInstr = inInstr[:fieldDepth]
+			}
+			continue
+		}
+		if fieldDepth > 0 && inInstr[fieldDepth-1] {
+			continue
+		}
+
+		switch {
+		case r == '\r' || r == 0x0B || r == 0x0C:
+			sb.WriteByte('\n')
+		case r == 0x07:
+			sb.WriteByte('\t') // 单元格/行结束标记
+		case r == 0x1E:
+			sb.WriteByte('-') // 不间断连字符
+		case r == 0xA0:
+			sb.WriteByte(' ')
+		case r == '\t':
+			sb.WriteByte('\t')
+		case r < 0x20:
+			// 图片、脚注引用等对象占位符
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	lines := strings.Split(sb.String(), "\n")
+	var cleaned []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, "\n")
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ============================================
+// DOCX (Office Open XML) 文本提取
+// ============================================
+
+const (
+	docxWordNS = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	docxRelsNS = "http://schemas.openxmlformats.org/package/2006/relationships"
+)
+
+// docxFrame 解析 document.xml 时的容器栈帧（段落 / 表格 / 行 / 单元格）
+type docxFrame struct {
+	kind  string // p / tbl / tr / tc
+	text  strings.Builder
+	lines []string // 嵌套内容（文本框、单元格内段落等）产出的行
+	cells []string // 仅 tr 使用
+}
+
+// extractFromDOCX 解析 .docx 包，按阅读顺序输出页眉、正文（段落/表格/文本框）、页脚文本
+func (a *App) extractFromDOCX(filePath string) string {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		log.Printf("[extractFromDOCX] 打开 DOCX 失败（可能已加密或损坏）: %v", err)
+		return ""
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	mainPart := docxMainPart(files)
+	body, ok := files[mainPart]
+	if !ok {
+		log.Printf("[extractFromDOCX] 未找到正文部件 %s: %s", mainPart, filePath)
+		return ""
+	}
+
+	// 页眉页脚与正文位于同一目录（通常为 word/）
+	dir := path.Dir(mainPart)
+	var headers, footers []string
+	for name := range files {
+		if path.Dir(name) != dir {
+			continue
+		}
+		base := path.Base(name)
+		switch {
+		case strings.HasPrefix(base, "header") && strings.HasSuffix(base, ".xml"):
+			headers = append(headers, name)
+		case strings.HasPrefix(base, "footer") && strings.HasSuffix(base, ".xml"):
+			footers = append(footers, name)
+		}
+	}
+	sort.Strings(headers)
+	sort.Strings(footers)
+
+	var out []string
+	seen := map[string]bool{} // 首页/奇偶页页眉常常内容相同，去重
+	appendPart := func(name string, dedupe bool) {
+		lines, err := docxPartLines(files[name])
+		if err != nil {
+			log.Printf("[extractFromDOCX] 解析 %s 失败: %v", name, err)
+			return
+		}
+		for _, line := range lines {
+			if dedupe {
+				if seen[line] {
+					continue
+				}
+				seen[line] = true
+			}
+			out = append(out, line)
+		}
+	}
+
+	for _, name := range headers {
+		appendPart(name, true)
+	}
+	appendPart(body.Name, false)
+	for _, name := range footers {
+		appendPart(name, true)
+	}
+
+	result := strings.Join(out, "\n")
+	if strings.TrimSpace(result) == "" {
+		log.Printf("[extractFromDOCX] DOCX 提取结果为空: %s", filePath)
+		return ""
+	}
+
+	log.Printf("[extractFromDOCX] 提取成功: %s, 长度=%d 字符", filepath.Base(filePath), len(result))
+	result = limitContent(result)
+	return result
+}
+
+// docxMainPart 通过 _rels/.rels 找到正文部件路径，找不到时使用默认的 word/document.xml
+func docxMainPart(files map[string]*zip.File) string {
+	const fallback = "word/document.xml"
+	rels, ok := files["_rels/.rels"]
+	if !ok {
+		return fallback
+	}
+	rc, err := rels.Open()
+	if err != nil {
+		return fallback
+	}
+	defer rc.Close()
+
+	var doc struct {
+		XMLName       xml.Name `xml:"Relationships"`
+		Relationships []struct {
+			Type   string `xml:"Type,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&doc); err != nil || doc.XMLName.Space != docxRelsNS {
+		return fallback
+	}
+	for _, rel := range doc.Relationships {
+		if strings.HasSuffix(rel.Type, "/officeDocument") {
+			return strings.TrimPrefix(path.Clean("/"+rel.Target), "/")
+		}
+	}
+	return fallback
+}
+
+// docxPartLines 流式解析一个 WordprocessingML 部件，返回按顺序排列的非空文本行
+func docxPartLines(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		out    []string
+		stack  []*docxFrame
+		inText bool
+	)
+
+	// deliver 把完成的行交给上一层容器；栈空时直接输出
+	deliver := func(lines []string) {
+		if len(stack) == 0 {
+			out = append(out, lines...)
+			return
+		}
+		top := stack[len(stack)-1]
+		top.lines = append(top.lines, lines...)
+	}
+	// currentParagraph 返回最内层的段落帧（文本写入目标）
+	currentParagraph := func() *docxFrame {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].kind == "p" {
+				return stack[i]
+			}
+		}
+		return nil
+	}
+	pop := func(kind string) *docxFrame {
+		if len(stack) == 0 || stack[len(stack)-1].kind != kind {
+			return nil
+		}
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return top
+	}
+
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return out, fmt.Errorf("XML 解析失败: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			// 兼容性内容的 Fallback 分支与 Choice 重复（如 VML 文本框），直接跳过
+			if t.Name.Local == "Fallback" {
+				if err := dec.Skip(); err != nil {
+					return out, err
+				}
+				continue
+			}
+			if t.Name.Space != docxWordNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "p", "tbl", "tr", "tc":
+				stack = append(stack, &docxFrame{kind: t.Name.Local})
+			case "t":
+				inText = true
+			case "tab":
+				if p := currentParagraph(); p != nil {
+					p.text.WriteString("\t")
+				}
+			case "br", "cr":
+				if p := currentParagraph(); p != nil {
+					p.text.WriteString("\n")
+				}
+			}
+
+		case xml.EndElement:
+			if t.Name.Space != docxWordNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				p := pop("p")
+				if p == nil {
+					continue
+				}
+				var lines []string
+				for _, line := range strings.Split(p.text.String(), "\n") {
+					if line = strings.TrimSpace(line); line != "" {
+						lines = append(lines, line)
+					}
+				}
+				deliver(append(lines, p.lines...))
+			case "tc":
+				tc := pop("tc")
+				if tc == nil {
+					continue
+				}
+				if len(stack) > 0 && stack[len(stack)-1].kind == "tr" {
+					tr := stack[len(stack)-1]
+					tr.cells = append(tr.cells, strings.Join(tc.lines, " "))
+				} else {
+					deliver(tc.lines)
+				}
+			case "tr":
+				tr := pop("tr")
+				if tr == nil {
+					continue
+				}
+				var cells []string
+				for _, c := range tr.cells {
+					if c = strings.TrimSpace(c); c != "" {
+						cells = append(cells, c)
+					}
+				}
+				if len(cells) > 0 {
+					deliver([]string{strings.Join(cells, " | ")})
+				}
+			case "tbl":
+				if tbl := pop("tbl"); tbl != nil {
+					deliver(tbl.lines)
+				}
+			}
+
+		case xml.CharData:
+			if !inText {
+				continue
+			}
+			if p := currentParagraph(); p != nil {
+				p.text.Write(t)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// docxPageCount 读取 docProps/app.xml 中 Word 保存时记录的页数，缺失时返回 0
+func docxPageCount(filePath string) int {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return 0
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name != "docProps/app.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return 0
+		}
+		defer rc.Close()
+		var props struct {
+			Pages int `xml:"Pages"`
+		}
+		if xml.NewDecoder(rc).Decode(&props) != nil {
+			return 0
+		}
+		return props.Pages
+	}
+	return 0
+}
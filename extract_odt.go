@@ -0,0 +1,233 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// ODT (OpenDocument Text) 文本提取
+// ============================================
+
+const (
+	odtTextNS  = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odtTableNS = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odtMetaNS  = "urn:oasis:names:tc:opendocument:xmlns:meta:1.0"
+	odtMime    = "application/vnd.oasis.opendocument.text"
+)
+
+// odtFrame 解析 content.xml 时的容器栈帧（段落 / 表格行 / 单元格）
+type odtFrame struct {
+	kind  string // p / row / cell
+	text  strings.Builder
+	lines []string
+	cells []string
+}
+
+// extractFromODT 解析 .odt 包中的 content.xml，按阅读顺序输出段落与表格
+func (a *App) extractFromODT(filePath string) (string, int, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", 0, fmt.Errorf("ODT 文件损坏: %v", err)
+	}
+	defer zr.Close()
+
+	var content, meta *zip.File
+	for _, f := range zr.File {
+		switch f.Name {
+		case "content.xml":
+			content = f
+		case "meta.xml":
+			meta = f
+		}
+	}
+	if content == nil {
+		return "", 0, fmt.Errorf("ODT 文件缺少 content.xml（可能已加密）")
+	}
+
+	rc, err := content.Open()
+	if err != nil {
+		return "", 0, err
+	}
+	defer rc.Close()
+	lines, err := odtLines(rc)
+	if err != nil {
+		log.Printf("[extractFromODT] 解析 content.xml 失败: %v", err)
+	}
+
+	result := strings.Join(lines, "\n")
+	if strings.TrimSpace(result) == "" {
+		log.Printf("[extractFromODT] ODT 提取结果为空: %s", filePath)
+		return "", odtPageCount(meta), nil
+	}
+	log.Printf("[extractFromODT] 提取成功: %s, 长度=%d 字符", filepath.Base(filePath), len(result))
+	return limitContent(result), odtPageCount(meta), nil
+}
+
+// odtLines 流式解析 content.xml
+func odtLines(r io.Reader) ([]string, error) {
+	var (
+		out   []string
+		stack []*odtFrame
+	)
+	deliver := func(lines []string) {
+		if len(stack) == 0 {
+			out = append(out, lines...)
+			return
+		}
+		top := stack[len(stack)-1]
+		top.lines = append(top.lines, lines...)
+	}
+	currentParagraph := func() *odtFrame {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].kind == "p" {
+				return stack[i]
+			}
+		}
+		return nil
+	}
+	pop := func(kind string) *odtFrame {
+		if len(stack) == 0 || stack[len(stack)-1].kind != kind {
+			return nil
+		}
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return top
+	}
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return out, fmt.Errorf("XML 解析失败: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odtTextNS && (t.Name.Local == "p" || t.Name.Local == "h"):
+				stack = append(stack, &odtFrame{kind: "p"})
+			case t.Name.Space == odtTableNS && t.Name.Local == "table-row":
+				stack = append(stack, &odtFrame{kind: "row"})
+			case t.Name.Space == odtTableNS && t.Name.Local == "table-cell":
+				stack = append(stack, &odtFrame{kind: "cell"})
+			case t.Name.Space == odtTextNS && t.Name.Local == "s":
+				// <text:s text:c="3"/> 表示连续空格
+				n := 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "c" {
+						if v, err := strconv.Atoi(attr.Value); err == nil && v > 0 {
+							n = v
+						}
+					}
+				}
+				if p := currentParagraph(); p != nil {
+					p.text.WriteString(strings.Repeat(" ", n))
+				}
+			case t.Name.Space == odtTextNS && t.Name.Local == "tab":
+				if p := currentParagraph(); p != nil {
+					p.text.WriteString("\t")
+				}
+			case t.Name.Space == odtTextNS && t.Name.Local == "line-break":
+				if p := currentParagraph(); p != nil {
+					p.text.WriteString("\n")
+				}
+			case t.Name.Space == odtTextNS && (t.Name.Local == "note-citation" || t.Name.Local == "tracked-changes"):
+				if err := dec.Skip(); err != nil {
+					return out, err
+				}
+			case t.Name.Local == "annotation":
+				if err := dec.Skip(); err != nil {
+					return out, err
+				}
+			}
+
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == odtTextNS && (t.Name.Local == "p" || t.Name.Local == "h"):
+				p := pop("p")
+				if p == nil {
+					continue
+				}
+				var lines []string
+				for _, line := range strings.Split(p.text.String(), "\n") {
+					if line = strings.TrimSpace(line); line != "" {
+						lines = append(lines, line)
+					}
+				}
+				deliver(append(lines, p.lines...))
+			case t.Name.Space == odtTableNS && t.Name.Local == "table-cell":
+				cell := pop("cell")
+				if cell == nil {
+					continue
+				}
+				if len(stack) > 0 && stack[len(stack)-1].kind == "row" {
+					row := stack[len(stack)-1]
+					row.cells = append(row.cells, strings.Join(cell.lines, " "))
+				} else {
+					deliver(cell.lines)
+				}
+			case t.Name.Space == odtTableNS && t.Name.Local == "table-row":
+				row := pop("row")
+				if row == nil {
+					continue
+				}
+				var cells []string
+				for _, c := range row.cells {
+					if c = strings.TrimSpace(c); c != "" {
+						cells = append(cells, c)
+					}
+				}
+				if len(cells) > 0 {
+					deliver([]string{strings.Join(cells, " | ")})
+				}
+			}
+
+		case xml.CharData:
+			if p := currentParagraph(); p != nil {
+				p.text.Write(t)
+			}
+		}
+	}
+	return out, nil
+}
+
+// odtPageCount 读取 meta.xml 中的页数统计，缺失时返回 0
+func odtPageCount(meta *zip.File) int {
+	if meta == nil {
+		return 0
+	}
+	rc, err := meta.Open()
+	if err != nil {
+		return 0
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Space != odtMetaNS || se.Name.Local != "document-statistic" {
+			continue
+		}
+		for _, attr := range se.Attr {
+			if attr.Name.Local == "page-count" {
+				n, _ := strconv.Atoi(attr.Value)
+				return n
+			}
+		}
+		return 0
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// ============================================
+// RTF 文本提取
+// ============================================
+
+// rtfSkipDestinations 不含正文的目标组（字体表单独解析字符集）
+var rtfSkipDestinations = map[string]bool{
+	"colortbl": true, "stylesheet": true, "info": true, "pict": true, "object": true,
+	"themedata": true, "colorschememapping": true, "datastore": true, "latentstyles": true,
+	"listtable": true, "listoverridetable": true, "rsidtbl": true, "generator": true,
+	"xmlnstbl": true, "mmathPr": true, "fldinst": true, "filetbl": true, "revtbl": true,
+}
+
+// rtfSymbols 输出固定字符的控制字
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+	"tab": "\t", "cell": " | ",
+	"emdash": "—", "endash": "–", "bullet": "•",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+}
+
+// rtfCharsetCodepages \fcharset 到代码页
+var rtfCharsetCodepages = map[int]int{
+	0: 1252, 128: 932, 129: 949, 134: 936, 136: 950, 204: 1251, 238: 1250,
+}
+
+// rtfGroup 解析时的组状态
+type rtfGroup struct {
+	skip     bool
+	fontTbl  bool
+	uc       int // \uN 之后需要跳过的替代字符数
+	codepage int
+}
+
+// rtfCodepageEncoding 代码页对应的解码器，未知代码页返回 nil（按 Windows-1252 处理）
+func rtfCodepageEncoding(cp int) encoding.Encoding {
+	switch cp {
+	case 936, 54936:
+		return simplifiedchinese.GB18030
+	case 950:
+		return traditionalchinese.Big5
+	case 932:
+		return japanese.ShiftJIS
+	case 949:
+		return korean.EUCKR
+	case 1250:
+		return charmap.Windows1250
+	case 1251:
+		return charmap.Windows1251
+	}
+	return nil
+}
+
+// rtfToText 解析 RTF：处理组、控制字、\'hh 代码页字节与 \uN Unicode 字符
+func rtfToText(data []byte) string {
+	var out strings.Builder
+	var pendingBytes []byte
+	pendingCP := 1252
+
+	defaultCP := 1252
+	fontCP := map[int]int{}
+	curFontDef := -1
+
+	stack := []rtfGroup{{uc: 1, codepage: defaultCP}}
+	top := func() *rtfGroup { return &stack[len(stack)-1] }
+	skipChars := 0
+
+	flushBytes := func() {
+		if len(pendingBytes) == 0 {
+			return
+		}
+		if enc := rtfCodepageEncoding(pendingCP); enc != nil {
+			if s, err := enc.NewDecoder().Bytes(pendingBytes); err == nil {
+				out.Write(s)
+				pendingBytes = pendingBytes[:0]
+				return
+			}
+		}
+		s, _ := charmap.Windows1252.NewDecoder().Bytes(pendingBytes)
+		out.Write(s)
+		pendingBytes = pendingBytes[:0]
+	}
+	emit := func(s string) {
+		if top().skip {
+			return
+		}
+		if skipChars > 0 {
+			skipChars--
+			return
+		}
+		flushBytes()
+		out.WriteString(s)
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '{':
+			flushBytes()
+			stack = append(stack, *top())
+			// 新组的第一个控制字决定是否为目标组，由下方控制字处理
+		case '}':
+			flushBytes()
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case '\r', '\n':
+		case '\\':
+			if i+1 >= len(data) {
+				break
+			}
+			next := data[i+1]
+			switch {
+			case next == '\\' || next == '{' || next == '}':
+				emit(string(next))
+				i++
+			case next == '\'':
+				// \'hh：当前代码页下的一个字节
+				if i+3 < len(data) {
+					if v, err := strconv.ParseUint(string(data[i+2:i+4]), 16, 8); err == nil && !top().skip {
+						if skipChars > 0 {
+							skipChars--
+						} else {
+							if len(pendingBytes) == 0 {
+								pendingCP = top().codepage
+							}
+							pendingBytes = append(pendingBytes, byte(v))
+						}
+					}
+				}
+				i += 3
+			case next == '*':
+				// \* 开头的目标组为可忽略的扩展内容
+				top().skip = true
+				i++
+			case next == '~':
+				emit(" ")
+				i++
+			case next == '_':
+				emit("-")
+				i++
+			case next == '\r' || next == '\n':
+				// 反斜杠加换行等同于 \par
+				emit("\n")
+				i++
+			case next == '-':
+				i++
+			case isASCIILetter(next):
+				j := i + 1
+				for j < len(data) && isASCIILetter(data[j]) {
+					j++
+				}
+				word := string(data[i+1 : j])
+				k := j
+				if k < len(data) && (data[k] == '-' || isASCIIDigit(data[k])) {
+					k++
+					for k < len(data) && isASCIIDigit(data[k]) {
+						k++
+					}
+				}
+				param, hasParam := 0, k > j
+				if hasParam {
+					param, _ = strconv.Atoi(string(data[j:k]))
+				}
+				// 控制字后的一个空格是分隔符
+				if k < len(data) && data[k] == ' ' {
+					k++
+				}
+				i = k - 1
+
+				g := top()
+				switch word {
+				case "ansicpg":
+					defaultCP = param
+					g.codepage = param
+				case "fonttbl":
+					g.fontTbl = true
+					g.skip = true
+				case "f":
+					if g.fontTbl {
+						curFontDef = param
+					} else if cp, ok := fontCP[param]; ok {
+						g.codepage = cp
+					} else {
+						g.codepage = defaultCP
+					}
+				case "fcharset":
+					if g.fontTbl && curFontDef >= 0 {
+						if cp, ok := rtfCharsetCodepages[param]; ok {
+							fontCP[curFontDef] = cp
+						}
+					}
+				case "uc":
+					g.uc = param
+				case "u":
+					if hasParam {
+						if param < 0 {
+							param += 65536
+						}
+						emit(string(rune(param)))
+						skipChars = g.uc
+					}
+				default:
+					if rtfSkipDestinations[word] {
+						g.skip = true
+					} else if s, ok := rtfSymbols[word]; ok {
+						emit(s)
+					}
+				}
+			default:
+				i++
+			}
+		default:
+			if top().skip {
+				continue
+			}
+			if skipChars > 0 {
+				skipChars--
+				continue
+			}
+			flushBytes()
+			out.WriteByte(c)
+		}
+	}
+	flushBytes()
+	return cleanExtractedLines(out.String())
+}
+
+// cleanExtractedLines 去掉行首尾空白、表格行多余的分隔符和空行
+func cleanExtractedLines(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.Trim(line, "|"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isASCIILetter 判断 ASCII 字母
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isASCIIDigit 判断 ASCII 数字
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isRTF 判断内容是否为 RTF
+func isRTF(head []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(head, " \r\n\t\xef\xbb\xbf"), []byte(`{\rtf`))
+}
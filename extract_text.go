@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// ============================================
+// 纯文本 / Markdown / HTML 文本提取
+// ============================================
+
+// htmlDropTags 内容与简历无关、整个元素（含内容）都要去掉的标签
+var htmlDropTags = []string{"script", "style", "head", "noscript", "svg", "nav", "footer"}
+
+// htmlDropRes 每个标签单独匹配，开闭标签必须是同一元素；\b 避免 <head 匹配到 <header（通常包含姓名与联系方式）
+var htmlDropRes = func() []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(htmlDropTags))
+	for i, tag := range htmlDropTags {
+		res[i] = regexp.MustCompile(`(?is)<` + tag + `\b[^>]*>.*?</` + tag + `\s*>`)
+	}
+	return res
+}()
+
+var (
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBreakRe   = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|table|section|article|ul|ol|header)>`)
+	htmlItemRe    = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlCellRe    = regexp.MustCompile(`(?i)</t[dh]>`)
+	htmlTagRe     = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlCharsetRe = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?([\w\-]+)`)
+)
+
+// decodeTextBytes 识别 BOM（UTF-8/UTF-16）；不是合法 UTF-8 时按 GB18030 解码（Windows 记事本保存的中文文本）
+func decodeTextBytes(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:])
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		if s, err := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Bytes(data); err == nil {
+			return string(s)
+		}
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		if s, err := unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Bytes(data); err == nil {
+			return string(s)
+		}
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	if s, err := simplifiedchinese.GB18030.NewDecoder().Bytes(data); err == nil {
+		return string(s)
+	}
+	return string(data)
+}
+
+// extractFromText 读取 .txt / .md
+func (a *App) extractFromText(filePath string) string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+	return a.truncateContent(strings.TrimSpace(decodeTextBytes(data)), maxContentBytes)
+}
+
+// extractFromHTML 读取保存的网页（如领英、招聘网站的简历页），按 <meta charset> 解码
+func (a *App) extractFromHTML(filePath string) string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+	text := ""
+	head := data[:min(len(data), 4096)]
+	if m := htmlCharsetRe.FindSubmatch(head); m != nil && !utf8.Valid(data) {
+		text = decodeCharset(data, string(m[1]))
+	} else {
+		text = decodeTextBytes(data)
+	}
+	return limitContent(htmlToText(text))
+}
+
+// htmlToText 把 HTML 转换为纯文本，保留段落换行、列表项与表格单元格分隔
+func htmlToText(s string) string {
+	s = htmlCommentRe.ReplaceAllString(s, "")
+	for _, re := range htmlDropRes {
+		s = re.ReplaceAllString(s, "")
+	}
+	s = htmlItemRe.ReplaceAllString(s, "\n• ")
+	s = htmlBreakRe.ReplaceAllString(s, "\n")
+	s = htmlCellRe.ReplaceAllString(s, " | ")
+	s = htmlTagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		line = strings.TrimSpace(strings.Trim(line, "|"))
+		if line != "" && line != "•" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ============================================
+// 文本提取质量报告
+// ============================================
+
+// minExtractionQuality 低于该质量分的简历在分析结果中标记为"输入不可靠"
+const minExtractionQuality = 40
+
+// maxContentBytes 保存的简历文本上限
+const maxContentBytes = 50000
+
+// ExtractionReport 简历文本提取结果，帮助判断低分是否源于文件无法识别
+type ExtractionReport struct {
+	Extractor       string   `json:"extractor"`               // docx / doc / pdf / pdf-ocr / ocr / text / raw
+	DetectedType    string   `json:"detected_type,omitempty"` // 按内容识别出的类型（MIME）
+	CharCount       int      `json:"char_count"`              // 提取到的字符数（按 rune 计）
+	PageCount       int      `json:"page_count"`              // 页数，0 表示未知
+	Language        string   `json:"language"`                // zh / en / mixed / unknown
+	Warnings        []string `json:"warnings"`                // 质量问题说明
+	UsedPlaceholder bool     `json:"used_placeholder"`        // 提取失败，内容为文件名占位
+	Quality         int      `json:"quality"`                 // 0-100 的质量评估
+}
+
+// assessExtraction 根据提取出的文本计算字符数、语言、乱码比例与质量分
+func assessExtraction(report *ExtractionReport, content string) {
+	report.CharCount = utf8.RuneCountInString(content)
+	report.Language = detectLanguage(content)
+
+	if report.UsedPlaceholder {
+		report.Quality = 0
+		report.Warnings = append(report.Warnings, "未能提取到简历文本，分析将仅基于文件名")
+		return
+	}
+
+	quality := 100
+	switch {
+	case report.CharCount < 100:
+		quality -= 60
+		report.Warnings = append(report.Warnings, fmt.Sprintf("提取到的文本过少（%d 字符）", report.CharCount))
+	case report.CharCount < 300:
+		quality -= 30
+		report.Warnings = append(report.Warnings, fmt.Sprintf("提取到的文本偏少（%d 字符）", report.CharCount))
+	}
+
+	if ratio := garbledRatio(content); ratio > 0.05 {
+		quality -= int(ratio * 400)
+		report.Warnings = append(report.Warnings, fmt.Sprintf("疑似乱码（%.0f%% 的字符无法识别）", ratio*100))
+	}
+
+	switch report.Extractor {
+	case "raw":
+		quality -= 40
+		report.Warnings = append(report.Warnings, "未找到对应格式的解析器，按原始字节读取，内容可能不可读")
+	case "ocr", "pdf-ocr":
+		quality -= 10
+		report.Warnings = append(report.Warnings, "文本来自 OCR 识别，可能存在识别误差")
+	}
+
+	if report.Language == "unknown" && report.CharCount > 0 {
+		report.Warnings = append(report.Warnings, "无法识别文本语言")
+	}
+
+	report.Quality = int(clampFloat(float64(quality), 0, 100))
+}
+
+// detectLanguage 按汉字与拉丁字母的比例粗略判断语言
+func detectLanguage(content string) string {
+	han, latin := 0, 0
+	for _, r := range content {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case r < unicode.MaxLatin1 && unicode.IsLetter(r):
+			latin++
+		}
+	}
+	// 中文一个字约等于英文一个单词，按 5 个字母折算
+	words := latin / 5
+	if han+words < 10 {
+		return "unknown"
+	}
+	ratio := float64(han) / float64(han+words)
+	switch {
+	case ratio > 0.6:
+		return "zh"
+	case ratio < 0.1:
+		return "en"
+	default:
+		return "mixed"
+	}
+}
+
+// garbledRatio 统计非法 UTF-8、控制字符、私用区字符所占比例
+func garbledRatio(content string) float64 {
+	total, bad := 0, 0
+	for _, r := range content {
+		total++
+		switch {
+		case r == utf8.RuneError:
+			bad++
+		case r == '\n' || r == '\t' || r == '\r':
+		case unicode.IsControl(r):
+			bad++
+		case unicode.Is(unicode.Co, r):
+			bad++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(bad) / float64(total)
+}
+
+// extractionWarning 生成附加到分析结果上的提示，质量合格时返回空
+func extractionWarning(report *ExtractionReport) string {
+	if report == nil || report.Quality >= minExtractionQuality {
+		return ""
+	}
+	msg := fmt.Sprintf("简历文本提取质量较低（%d/100），评分可能不可靠", report.Quality)
+	if len(report.Warnings) > 0 {
+		msg += "：" + strings.Join(report.Warnings, "；")
+	}
+	return msg
+}
+
+// limitContent 把提取结果限制在 maxContentBytes 内，不会切断多字节字符
+func limitContent(content string) string {
+	return truncateRunes(content, maxContentBytes)
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ============================================
+// 文本提取器注册表：按文件内容（MIME 嗅探）选择提取器，扩展名只用于区分无法从内容判断的格式
+// 导入白名单与文件对话框过滤器都由此生成
+// ============================================
+
+// TextExtractor 简历文本提取器；第三方格式可在 init() 中通过 RegisterExtractor 注册
+type TextExtractor interface {
+	Name() string         // 写入 ExtractionReport.Extractor
+	Extensions() []string // 扩展名（小写，含点）
+	MIMETypes() []string  // 能处理的内容类型（与 sniffMIME 的结果匹配）
+	Priority() int        // 同一扩展名/类型有多个提取器时优先级高者生效，内置提取器为 0
+	Extract(ctx context.Context, r io.Reader) (*ExtractedText, error)
+}
+
+// ExtractedText 提取结果
+type ExtractedText struct {
+	Text      string
+	PageCount int      // 0 表示未知
+	Warnings  []string // 写入 ExtractionReport.Warnings
+	Method    string   // 实际使用的方式（如 PDF 回退到 OCR 时为 pdf-ocr），为空时使用 Name()
+}
+
+// extractorLabeler 可选接口：文件对话框中的分组名
+type extractorLabeler interface {
+	Label() string
+}
+
+// genericMIMEs 无法据此区分具体格式的内容类型，此时以扩展名为准
+var genericMIMEs = map[string]bool{
+	"": true, "application/octet-stream": true, "text/plain": true, "application/zip": true,
+}
+
+var (
+	pluginMu         sync.Mutex
+	pluginExtractors []TextExtractor
+)
+
+// RegisterExtractor 注册第三方提取器（在 init() 中调用），对之后创建的 App 生效
+func RegisterExtractor(e TextExtractor) {
+	pluginMu.Lock()
+	defer pluginMu.Unlock()
+	pluginExtractors = append(pluginExtractors, e)
+	log.Printf("[RegisterExtractor] 注册提取器 %s: %v", e.Name(), e.Extensions())
+}
+
+// extractorRegistry 已注册的提取器
+type extractorRegistry struct {
+	mu   sync.RWMutex
+	list []TextExtractor
+}
+
+// extractors 返回 App 的提取器注册表（首次使用时创建：内置提取器 + 第三方提取器）
+func (a *App) extractors() *extractorRegistry {
+	a.extractorsOnce.Do(func() {
+		reg := &extractorRegistry{}
+		for _, e := range builtinExtractors(a) {
+			reg.register(e)
+		}
+		pluginMu.Lock()
+		for _, e := range pluginExtractors {
+			reg.register(e)
+		}
+		pluginMu.Unlock()
+		a.extractorReg = reg
+	})
+	return a.extractorReg
+}
+
+// register 添加提取器
+func (r *extractorRegistry) register(e TextExtractor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.list = append(r.list, e)
+}
+
+// matching 返回满足条件的提取器，按优先级从高到低（同优先级后注册者优先）
+func (r *extractorRegistry) matching(match func(TextExtractor) bool) []TextExtractor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []TextExtractor
+	for i := len(r.list) - 1; i >= 0; i-- {
+		if match(r.list[i]) {
+			out = append(out, r.list[i])
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Priority() > out[j].Priority() })
+	return out
+}
+
+// byExtension 能处理该扩展名的提取器
+func (r *extractorRegistry) byExtension(ext string) []TextExtractor {
+	return r.matching(func(e TextExtractor) bool { return containsFold(e.Extensions(), ext) })
+}
+
+// byMIME 能处理该内容类型的提取器
+func (r *extractorRegistry) byMIME(mime string) []TextExtractor {
+	return r.matching(func(e TextExtractor) bool { return containsFold(e.MIMETypes(), mime) })
+}
+
+// supportsExtension 是否有提取器声明了该扩展名
+func (r *extractorRegistry) supportsExtension(ext string) bool {
+	return len(r.byExtension(ext)) > 0
+}
+
+// detect 为文件选择提取器：内容类型明确时以内容为准（扩展名写错的文件也能正确处理），
+// 内容类型不足以区分格式时（纯文本、通用 ZIP 等）以扩展名为准；都无法识别时返回 nil
+func (r *extractorRegistry) detect(filePath string) (TextExtractor, string) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	mime := sniffMIME(filePath)
+	byExt := r.byExtension(ext)
+
+	if !genericMIMEs[mime] {
+		if byMime := r.byMIME(mime); len(byMime) > 0 {
+			// 扩展名对应的提取器也能处理该类型时优先使用（如 .md 与 .txt 都是 text/plain）
+			for _, e := range byExt {
+				if containsFold(e.MIMETypes(), mime) {
+					return e, mime
+				}
+			}
+			if len(byExt) > 0 || ext != "" {
+				log.Printf("[detect] %s 的内容为 %s，使用 %s 提取器", filepath.Base(filePath), mime, byMime[0].Name())
+			}
+			return byMime[0], mime
+		}
+	}
+	if len(byExt) > 0 {
+		return byExt[0], mime
+	}
+	if byMime := r.byMIME(mime); len(byMime) > 0 {
+		return byMime[0], mime
+	}
+	return nil, mime
+}
+
+// extensions 所有已注册的扩展名，按注册顺序
+func (r *extractorRegistry) extensions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seen := map[string]bool{}
+	var out []string
+	for _, e := range r.list {
+		for _, ext := range e.Extensions() {
+			if !seen[ext] {
+				seen[ext] = true
+				out = append(out, ext)
+			}
+		}
+	}
+	return out
+}
+
+// groups 文件对话框分组：分组名 -> 扩展名，按注册顺序
+func (r *extractorRegistry) groups() ([]string, map[string][]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var labels []string
+	exts := map[string][]string{}
+	for _, e := range r.list {
+		label := strings.ToUpper(e.Name()) + " 文件"
+		if l, ok := e.(extractorLabeler); ok {
+			label = l.Label()
+		}
+		if _, ok := exts[label]; !ok {
+			labels = append(labels, label)
+		}
+		exts[label] = append(exts[label], e.Extensions()...)
+	}
+	return labels, exts
+}
+
+// containsFold 忽略大小写判断是否包含
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffMIME 根据文件头判断内容类型；ZIP 容器进一步区分 DOCX / ODT
+func sniffMIME(filePath string) string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return "application/pdf"
+	case isRTF(head):
+		return "application/rtf"
+	case bytes.HasPrefix(head, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}):
+		return "application/msword"
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return sniffZipMIME(filePath)
+	}
+	mime := http.DetectContentType(head)
+	if i := strings.Index(mime, ";"); i != -1 {
+		mime = mime[:i]
+	}
+	return mime
+}
+
+// sniffZipMIME 区分 OOXML 与 OpenDocument
+func sniffZipMIME(filePath string) string {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "application/zip"
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		case "mimetype":
+			rc, err := f.Open()
+			if err != nil {
+				continue
+			}
+			data, _ := io.ReadAll(io.LimitReader(rc, 128))
+			rc.Close()
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return "application/zip"
+}
+
+// ============================================
+// 内置提取器
+// ============================================
+
+// builtinExtractor 基于文件路径实现的内置提取器
+type builtinExtractor struct {
+	name     string
+	label    string
+	exts     []string
+	mimes    []string
+	extract  func(ctx context.Context, filePath string) (*ExtractedText, error)
+	priority int
+}
+
+func (b *builtinExtractor) Name() string         { return b.name }
+func (b *builtinExtractor) Label() string        { return b.label }
+func (b *builtinExtractor) Extensions() []string { return b.exts }
+func (b *builtinExtractor) MIMETypes() []string  { return b.mimes }
+func (b *builtinExtractor) Priority() int        { return b.priority }
+
+// Extract 内置解析器需要文件路径（zip/PDF 需要随机读取，OCR 需要交给外部程序）：
+// 传入的是 *os.File 时直接使用其路径，否则先写入临时文件
+func (b *builtinExtractor) Extract(ctx context.Context, r io.Reader) (*ExtractedText, error) {
+	if f, ok := r.(*os.File); ok {
+		return b.extract(ctx, f.Name())
+	}
+	tmp, err := os.CreateTemp("", "talentlens-extract-*"+b.exts[0])
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	return b.extract(ctx, tmp.Name())
+}
+
+// builtinExtractors 内置格式，顺序即文件对话框中的顺序
+func builtinExtractors(a *App) []TextExtractor {
+	text := func(fn func(string) string) func(context.Context, string) (*ExtractedText, error) {
+		return func(_ context.Context, filePath string) (*ExtractedText, error) {
+			return &ExtractedText{Text: fn(filePath)}, nil
+		}
+	}
+	return []TextExtractor{
+		&builtinExtractor{name: "pdf", label: "PDF 文件", exts: []string{".pdf"},
+			mimes: []string{"application/pdf"}, extract: a.extractPDFText},
+		&builtinExtractor{name: "docx", label: "Word 文件", exts: []string{".docx"},
+			mimes: []string{"application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+			extract: func(_ context.Context, filePath string) (*ExtractedText, error) {
+				// DOCX 是 zip 包，原始字节没有可读内容，解析失败时直接返回空（由调用方使用占位内容）
+				return &ExtractedText{Text: a.extractFromDOCX(filePath), PageCount: docxPageCount(filePath)}, nil
+			}},
+		&builtinExtractor{name: "doc", label: "Word 文件", exts: []string{".doc"},
+			mimes: []string{"application/msword"},
+			extract: func(_ context.Context, filePath string) (*ExtractedText, error) {
+				content, err := a.extractFromDOC(filePath)
+				return &ExtractedText{Text: content}, err
+			}},
+		&builtinExtractor{name: "odt", label: "OpenDocument 文本", exts: []string{".odt"},
+			mimes: []string{odtMime},
+			extract: func(_ context.Context, filePath string) (*ExtractedText, error) {
+				content, pages, err := a.extractFromODT(filePath)
+				return &ExtractedText{Text: content, PageCount: pages}, err
+			}},
+		&builtinExtractor{name: "rtf", label: "RTF 文件", exts: []string{".rtf"},
+			mimes: []string{"application/rtf", "text/rtf"},
+			extract: text(func(filePath string) string {
+				data, err := os.ReadFile(filePath)
+				if err != nil {
+					return ""
+				}
+				return limitContent(rtfToText(data))
+			})},
+		&builtinExtractor{name: "html", label: "网页", exts: []string{".html", ".htm"},
+			mimes: []string{"text/html"}, extract: text(a.extractFromHTML)},
+		&builtinExtractor{name: "text", label: "文本文件", exts: []string{".txt", ".md", ".markdown"},
+			mimes: []string{"text/plain"}, extract: text(a.extractFromText)},
+		&builtinExtractor{name: "email", label: "邮件", exts: []string{".eml"},
+			mimes: []string{"message/rfc822"},
+			extract: func(_ context.Context, filePath string) (*ExtractedText, error) {
+				content, err := a.extractFromEmail(filePath)
+				return &ExtractedText{Text: content}, err
+			}},
+		&builtinExtractor{name: "ocr", label: "图片文件", exts: []string{".jpg", ".jpeg", ".png", ".bmp", ".gif", ".webp"},
+			mimes: []string{"image/jpeg", "image/png", "image/bmp", "image/gif", "image/webp"},
+			extract: func(ctx context.Context, filePath string) (*ExtractedText, error) {
+				// 图片的原始字节没有文字，只能通过 OCR 识别
+				res := &ExtractedText{PageCount: 1}
+				if a.ocrEngine() == nil {
+					res.Warnings = append(res.Warnings, "未启用 OCR，无法识别图片简历中的文字")
+				}
+				content, err := a.extractFromImage(ctx, filePath)
+				res.Text = content
+				return res, err
+			}},
+	}
+}
+
+// extractPDFText 优先读取文本层，没有文本层时尝试 OCR，最后回退到原始字节
+func (a *App) extractPDFText(ctx context.Context, filePath string) (*ExtractedText, error) {
+	content, pages := a.extractFromPDF(filePath)
+	res := &ExtractedText{Text: content, PageCount: pages}
+	if content != "" {
+		return res, nil
+	}
+	// 没有文本层（扫描版），尝试 OCR
+	if a.ocrEngine() != nil {
+		log.Println("[extractText] PDF 无文本层，尝试 OCR")
+		res.Method = "pdf-ocr"
+		text, err := a.extractPDFWithOCR(ctx, filePath)
+		if err != nil {
+			// 写入报告与 ExtractError，让用户知道扫描件为什么没有文本（OCR 程序缺失、超时等）
+			log.Printf("[extractText] PDF OCR 失败: %v", err)
+			res.Warnings = append(res.Warnings, "扫描版 PDF 的 OCR 识别失败")
+		}
+		res.Text = text
+		return res, err
+	}
+	res.Warnings = append(res.Warnings, "PDF 没有可提取的文本层（可能是扫描件），可在设置中启用 OCR")
+	// PDF 库提取失败时回退到原始方式
+	log.Println("[extractText] PDF 库提取失败，回退到原始方式")
+	res.Method = "raw"
+	res.Text = a.extractRawText(filePath)
+	return res, nil
+}
+
+// extractRawText 未知格式按原始字节读取
+func (a *App) extractRawText(filePath string) string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+	return a.extractFromBytes(data)
+}
+
+// runExtractor 打开文件并交给提取器，把结果写入报告
+func (a *App) runExtractor(ctx context.Context, e TextExtractor, filePath string, report *ExtractionReport) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("无法读取文件: %v", err)
+	}
+	defer f.Close()
+
+	res, err := e.Extract(ctx, f)
+	if res == nil {
+		if err == nil {
+			err = fmt.Errorf("提取器 %s 未返回结果", e.Name())
+		}
+		return "", err
+	}
+	if res.Method != "" {
+		report.Extractor = res.Method
+	}
+	if res.PageCount > 0 {
+		report.PageCount = res.PageCount
+	}
+	report.Warnings = append(report.Warnings, res.Warnings...)
+	return res.Text, err
+}
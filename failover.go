@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ============================================
+// 服务商故障转移：主配置之后按 Config.AIProfiles 的顺序尝试备用配置，
+// 连续失败的服务商熔断一段时间，期间直接跳过
+// ============================================
+
+const (
+	breakerFailureThreshold = 3               // 连续失败多少次后熔断
+	breakerCooldown         = 2 * time.Minute // 熔断时长，之后只放行一次试探请求
+)
+
+// providerBreaker 一个服务商的熔断状态
+type providerBreaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// aiProfileLabel 配置的显示名称，未命名时使用服务商与模型
+func aiProfileLabel(cfg *AIConfig) string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	provider := cfg.Provider
+	if provider == "" {
+		provider = providerKind(cfg)
+	}
+	return provider + "/" + cfg.Model
+}
+
+// aiProfiles 故障转移顺序：primary 在前，之后是已配置完整的备用配置（与 primary 相同的跳过）
+func (a *App) aiProfiles(primary *AIConfig) []AIConfig {
+	profiles := []AIConfig{*primary}
+	for _, p := range a.currentConfig().AIProfiles {
+		if p == *primary {
+			continue
+		}
+		if err := checkAIConfig(&p); err != nil {
+			log.Printf("[aiProfiles] 跳过备用配置 %s: %v", aiProfileLabel(&p), err)
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// breakerKey 熔断按服务商与模型计算：同一服务商的其他模型可能仍然可用
+func breakerKey(cfg *AIConfig) string {
+	return limiterKey(cfg) + "|" + cfg.Model
+}
+
+// breakerOpen 服务商是否处于熔断中，返回恢复时间；
+// 熔断到期后（半开）只放行第一个请求作为试探，其余并发请求继续跳过，直到 recordBreaker 记录试探结果
+func (a *App) breakerOpen(cfg *AIConfig) (bool, time.Time) {
+	a.breakersMu.Lock()
+	defer a.breakersMu.Unlock()
+	b := a.breakers[breakerKey(cfg)]
+	if b == nil || b.failures < breakerFailureThreshold {
+		return false, time.Time{}
+	}
+	now := time.Now()
+	if now.Before(b.openUntil) {
+		return true, b.openUntil
+	}
+	// 放行试探请求并顺延熔断时间；试探没有结果（取消、请求本身错误）时，顺延到期后再放行下一次
+	b.openUntil = now.Add(breakerCooldown)
+	log.Printf("[breakerOpen] %s 熔断到期，放行一次试探请求", aiProfileLabel(cfg))
+	return false, time.Time{}
+}
+
+// recordBreaker 记录一次请求结果；熔断恢复后的试探请求失败时立即重新熔断
+func (a *App) recordBreaker(cfg *AIConfig, ok bool) {
+	a.breakersMu.Lock()
+	defer a.breakersMu.Unlock()
+	key := breakerKey(cfg)
+	if ok {
+		delete(a.breakers, key)
+		return
+	}
+	if a.breakers == nil {
+		a.breakers = map[string]*providerBreaker{}
+	}
+	b := a.breakers[key]
+	if b == nil {
+		b = &providerBreaker{}
+		a.breakers[key] = b
+	}
+	b.failures++
+	if b.failures >= breakerFailureThreshold {
+		b.openUntil = time.Now().Add(breakerCooldown)
+		log.Printf("[recordBreaker] %s 连续失败 %d 次，熔断 %s", aiProfileLabel(cfg), b.failures, breakerCooldown)
+	}
+}
+
+// isFailoverError 是否应切换到下一个服务商：服务端错误、网络超时、额度用尽、限流；
+// 请求本身有问题（参数错误、Key 无效等）时换服务商也无济于事
+func isFailoverError(err error) bool {
+	switch classifyAIError(err).Kind {
+	case AIErrServer, AIErrNetwork, AIErrQuota, AIErrRateLimited:
+		return true
+	}
+	return false
+}
+
+// chatWithFailover 依次尝试各配置直到成功，返回回复与实际使用的配置
+func (a *App) chatWithFailover(ctx context.Context, primary *AIConfig, req *ChatRequest) (*ChatResult, *AIConfig, error) {
+	profiles := a.aiProfiles(primary)
+	var errs []string
+	var lastErr error
+	for i := range profiles {
+		cfg := &profiles[i]
+		label := aiProfileLabel(cfg)
+		if open, until := a.breakerOpen(cfg); open {
+			log.Printf("[chatWithFailover] %s 熔断中，跳过", label)
+			lastErr = &AIError{Kind: AIErrServer, Err: fmt.Errorf("%s 连续请求失败，已暂停使用，%s 后恢复", label, until.Format("15:04:05"))}
+			errs = append(errs, lastErr.Error())
+			continue
+		}
+
+		res, err := a.chatWithRetry(ctx, cfg, req)
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		if err == nil {
+			a.recordBreaker(cfg, true)
+			if i > 0 {
+				log.Printf("[chatWithFailover] 已切换到备用配置 %s", label)
+			}
+			return res, cfg, nil
+		}
+		if !isFailoverError(err) {
+			return nil, nil, err
+		}
+		a.recordBreaker(cfg, false)
+		log.Printf("[chatWithFailover] %s 不可用，尝试下一个配置: %v", label, err)
+		lastErr = err
+		errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+	}
+	if len(errs) == 1 {
+		return nil, nil, lastErr
+	}
+	// 保留最后一个错误的类型，便于界面提示
+	return nil, nil, fmt.Errorf("所有 AI 配置均不可用: %s: %w", strings.Join(errs[:len(errs)-1], "; "), lastErr)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ============================================
+// 原始简历文件托管：导入时复制到 files/<hash>/ 下，不再依赖用户的原始位置
+// ============================================
+
+// resumeFileMimes 预览原始文件时返回的 MIME 类型
+var resumeFileMimes = map[string]string{
+	".pdf":  "application/pdf",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".doc":  "application/msword",
+	".txt":  "text/plain",
+	".md":   "text/markdown",
+}
+
+// maxPreviewBytes 预览接口一次返回的文件大小上限
+const maxPreviewBytes = 20 << 20
+
+// getFilesDir 托管原始文件的目录
+func (a *App) getFilesDir() string {
+	dir := filepath.Join(a.getDataDir(), "files")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// isManagedFile 判断路径是否已位于托管目录中
+func (a *App) isManagedFile(path string) bool {
+	rel, err := filepath.Rel(a.getFilesDir(), path)
+	return err == nil && !strings.HasPrefix(rel, "..") && !filepath.IsAbs(rel)
+}
+
+// storeOriginal 把原始文件复制到 files/<hash>/<文件名>；相同内容的文件只保存一份
+func (a *App) storeOriginal(srcPath, hash, fileName string) (string, error) {
+	if hash == "" {
+		return "", fmt.Errorf("文件哈希为空")
+	}
+	dir := filepath.Join(a.getFilesDir(), hash)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(dir, filepath.Base(fileName))
+	if _, err := os.Stat(dst); err == nil {
+		return dst, nil
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	// 先写临时文件再重命名，避免中途失败留下不完整的副本
+	tmp, err := os.CreateTemp(dir, ".import-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return dst, nil
+}
+
+// adoptOriginal 把简历指向托管副本；已托管或原文件不存在时不做处理，复制失败时保留原路径
+func (a *App) adoptOriginal(r *Resume) bool {
+	if r.FilePath == "" || r.FilePath == r.FileName || a.isManagedFile(r.FilePath) {
+		return false
+	}
+	if _, err := os.Stat(r.FilePath); err != nil {
+		return false
+	}
+	if r.FileHash == "" {
+		r.FileHash = fileSHA256(r.FilePath)
+	}
+	managed, err := a.storeOriginal(r.FilePath, r.FileHash, r.FileName)
+	if err != nil {
+		log.Printf("[adoptOriginal] 复制原始文件失败: %s, %v", r.FilePath, err)
+		return false
+	}
+	r.OriginalPath = r.FilePath
+	r.FilePath = managed
+	return true
+}
+
+// releaseOriginal 删除简历时清理托管副本（仍被其他简历引用时保留）
+func (a *App) releaseOriginal(r *Resume) {
+	if !a.isManagedFile(r.FilePath) {
+		return
+	}
+	for _, other := range a.GetResumes() {
+		if other.ID != r.ID && other.FilePath == r.FilePath {
+			return
+		}
+	}
+	os.Remove(r.FilePath)
+	// 目录为空时一并删除
+	os.Remove(filepath.Dir(r.FilePath))
+}
+
+// existingResumeFile 返回简历原始文件的可用路径：优先托管副本，其次用户原始位置；都不存在时返回空
+func existingResumeFile(r *Resume) string {
+	for _, p := range []string{r.FilePath, r.OriginalPath} {
+		if p == "" || p == r.FileName {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// resumeFile 读取简历记录并定位其原始文件
+func (a *App) resumeFile(id string) (*Resume, string, error) {
+	r := a.loadResume(id)
+	if r == nil {
+		return nil, "", fmt.Errorf("简历不存在")
+	}
+	path := existingResumeFile(r)
+	if path == "" {
+		return r, "", fmt.Errorf("原始文件不存在: %s", r.FileName)
+	}
+	return r, path, nil
+}
+
+// OpenResumeFile 用系统默认程序打开简历原始文件
+func (a *App) OpenResumeFile(id string) error {
+	_, path, err := a.resumeFile(id)
+	if err != nil {
+		return err
+	}
+	runtime.BrowserOpenURL(a.ctx, path)
+	return nil
+}
+
+// PreviewResumeFile 返回原始文件内容（base64）供前端预览
+func (a *App) PreviewResumeFile(id string) (map[string]interface{}, error) {
+	r, path, err := a.resumeFile(id)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxPreviewBytes {
+		return nil, fmt.Errorf("文件过大（%d MB），请使用外部程序打开", info.Size()>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	mime := resumeFileMimes[ext]
+	if mime == "" {
+		mime = ocrImageExts[ext]
+	}
+	if mime == "" {
+		mime = "application/octet-stream"
+	}
+	return map[string]interface{}{
+		"fileName": r.FileName,
+		"mime":     mime,
+		"size":     info.Size(),
+		"data":     base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// ExportResumeFile 弹出保存对话框，把原始文件另存到用户选择的位置，返回保存路径（取消时为空）
+func (a *App) ExportResumeFile(id string) (string, error) {
+	r, path, err := a.resumeFile(id)
+	if err != nil {
+		return "", err
+	}
+	dst, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出原始简历",
+		DefaultFilename: r.FileName,
+	})
+	if err != nil || dst == "" {
+		return "", err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", fmt.Errorf("创建文件失败: %v", err)
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return "", fmt.Errorf("写入文件失败: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	log.Printf("[ExportResumeFile] 已导出: %s -> %s", r.FileName, dst)
+	return dst, nil
+}
@@ -14,6 +14,7 @@ export interface Project {
     education_level: string
   }
   resume_ids: string[]
+  budget_limit?: number
   status: 'draft' | 'analyzing' | 'completed'
   created_at: string
   updated_at: string
@@ -123,6 +124,21 @@ export const useProjectStore = defineStore('project', () => {
     } catch {}
   }
 
+  // 设置项目预算（美元），0 表示不限制
+  async function setProjectBudget(id: string, limit: number): Promise<boolean> {
+    await loadWails()
+    if (!isWailsEnv || !WailsApp) return false
+    try {
+      await WailsApp.SetProjectBudget(id, limit)
+      await refreshProject(id)
+      devLog('info', `项目预算已设置: ${id}, $${limit}`)
+      return true
+    } catch (err: any) {
+      devLog('error', `设置项目预算失败: ${err.message || err}`)
+      return false
+    }
+  }
+
   // 导出报告
   async function exportReport(projectId: string): Promise<string> {
     await loadWails()
@@ -167,6 +183,7 @@ export const useProjectStore = defineStore('project', () => {
     createProject,
     deleteProject,
     refreshProject,
+    setProjectBudget,
     exportReport,
     migrateExisting
   }
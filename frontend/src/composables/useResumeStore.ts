@@ -109,6 +109,7 @@ export const useResumeStore = defineStore('resume', () => {
   // 状态
   const resumes = ref<Resume[]>([])
   const isAnalyzing = ref(false)
+  const isPaused = ref(false)
   const selectedId = ref<string | null>(null)
   
   // 分析进度状态
@@ -248,7 +249,7 @@ export const useResumeStore = defineStore('resume', () => {
 
     // 检查 AI 配置
     const aiConfig = getAIConfig()
-    if (!aiConfig || !aiConfig.api_key) {
+    if (!isAIConfigured(aiConfig)) {
       devLog('error', '重新分析失败: 未配置 AI Key')
       return false
     }
@@ -263,7 +264,8 @@ export const useResumeStore = defineStore('resume', () => {
       try {
         resume.status = 'analyzing'
         devLog('info', `重新分析简历: ${resume.fileName}`)
-        await WailsApp.AnalyzeResume(id, aiConfig, jobConfig)
+        // 明确要求重新分析：忽略分析缓存，重新调用 AI
+        await WailsApp.ForceAnalyzeResume(id, aiConfig, jobConfig)
         // 结果通过 analysis:completed 事件回调更新
       } catch (err: any) {
         devLog('error', `重新分析失败: ${err.message || err}`)
@@ -323,6 +325,11 @@ export const useResumeStore = defineStore('resume', () => {
     '需要补充相关认证'
   ]
 
+  // AI 配置是否可用：本地 Ollama 不需要 API Key
+  function isAIConfigured(aiConfig: ReturnType<typeof getAIConfig>): boolean {
+    return !!aiConfig && (!!aiConfig.api_key || aiConfig.provider === 'ollama')
+  }
+
   // 获取 AI 配置
   function getAIConfig() {
     const saved = localStorage.getItem('goresume_settings')
@@ -334,8 +341,14 @@ export const useResumeStore = defineStore('resume', () => {
         base_url: settings.ai?.baseURL || 'https://api.deepseek.com/v1',
         api_key: settings.ai?.apiKey || '',
         model: settings.ai?.model || 'deepseek-chat',
+        deployment: settings.ai?.deployment || '',
+        api_version: settings.ai?.apiVersion || '',
         max_retries: 3,
-        timeout: 60
+        timeout: 60,
+        // 批量分析的并发与服务商限流（分析设置）
+        concurrency: settings.analysis?.maxConcurrent || 0,
+        rpm: settings.analysis?.rpm || 0,
+        tpm: settings.analysis?.tpm || 0
       }
     } catch {
       return null
@@ -387,7 +400,7 @@ export const useResumeStore = defineStore('resume', () => {
       const aiConfig = getAIConfig()
       const jobConfig = getJobConfig()
 
-      if (!aiConfig || !aiConfig.api_key) {
+      if (!isAIConfigured(aiConfig)) {
         devLog('error', 'AI 配置无效或缺少 API Key')
         isAnalyzing.value = false
         return
@@ -480,12 +493,12 @@ export const useResumeStore = defineStore('resume', () => {
     if (isAnalyzing.value) return false
 
     const aiConfig = getAIConfig()
-    if (!aiConfig || !aiConfig.api_key) {
+    if (!isAIConfigured(aiConfig)) {
       devLog('error', '批量重分析失败: 未配置 AI Key')
       return false
     }
 
-    // 将所有 done/error 简历重置为 pending
+    // 将所有 done/error 简历重置为 pending；后端同时标记为重新分析，不使用分析缓存
     let resetCount = 0
     for (const resume of resumes.value) {
       if (resume.status === 'done' || resume.status === 'error') {
@@ -493,6 +506,13 @@ export const useResumeStore = defineStore('resume', () => {
         resume.score = undefined
         resume.analysis = undefined
         resetCount++
+        if (isWailsEnv && WailsApp) {
+          try {
+            await WailsApp.ReAnalyzeResume(resume.id)
+          } catch (err: any) {
+            devLog('error', `重置简历失败: ${err.message || err}`)
+          }
+        }
       }
     }
 
@@ -627,9 +647,28 @@ export const useResumeStore = defineStore('resume', () => {
     WailsRuntime.EventsOn('batch:completed', (data: any) => {
       devLog('info', `批量分析完成, 共 ${data.total} 份`)
       isAnalyzing.value = false
+      isPaused.value = false
       batchProgress.value = { current: 0, total: 0, currentResumeId: null }
     })
 
+    // 监听批量分析取消（用户取消、AI 配置变更、项目删除、超出预算）
+    WailsRuntime.EventsOn('batch:cancelled', (data: any) => {
+      devLog('warn', `批量分析已停止: ${data.reason || '已取消'}, 完成 ${data.current}/${data.total}`)
+      isAnalyzing.value = false
+      isPaused.value = false
+      batchProgress.value = { current: 0, total: 0, currentResumeId: null }
+    })
+
+    // 监听批量分析暂停与继续
+    WailsRuntime.EventsOn('batch:paused', (data: any) => {
+      devLog('info', `批量分析已暂停: ${data.id}`)
+      isPaused.value = true
+    })
+    WailsRuntime.EventsOn('batch:resumed', (data: any) => {
+      devLog('info', `批量分析已继续: ${data.id}`)
+      isPaused.value = false
+    })
+
     console.log('✅ Wails 事件监听已初始化')
   }
 
@@ -662,6 +701,7 @@ export const useResumeStore = defineStore('resume', () => {
     // 状态
     resumes,
     isAnalyzing,
+    isPaused,
     selectedId,
     batchProgress,
     // 计算属性
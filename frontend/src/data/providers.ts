@@ -14,6 +14,8 @@ export interface Provider {
   defaultModel: string
   guide: ProviderGuide
   recommended?: boolean
+  editableBaseURL?: boolean // 需要填写自己的接口地址（Azure 资源地址、本地 Ollama）
+  keyOptional?: boolean     // 不需要 API Key（本地模型）
 }
 
 // 服务商预设配置
@@ -135,6 +137,88 @@ export const providers: Provider[] = [
       pricing: 'providers.siliconflow.pricing'
     }
   },
+  {
+    id: 'anthropic',
+    name: 'Anthropic Claude',
+    baseURL: 'https://api.anthropic.com',
+    models: [
+      { id: 'claude-sonnet-4-5', name: 'Claude Sonnet 4.5', recommended: true },
+      { id: 'claude-opus-4-1', name: 'Claude Opus 4.1' },
+      { id: 'claude-3-5-haiku-latest', name: 'Claude 3.5 Haiku' }
+    ],
+    defaultModel: 'claude-sonnet-4-5',
+    guide: {
+      title: 'providers.anthropic.guideTitle',
+      steps: [
+        'providers.anthropic.steps.0',
+        'providers.anthropic.steps.1',
+        'providers.anthropic.steps.2',
+        'providers.anthropic.steps.3'
+      ],
+      link: 'https://console.anthropic.com/settings/keys',
+      pricing: 'providers.anthropic.pricing'
+    }
+  },
+  {
+    id: 'gemini',
+    name: 'Google Gemini',
+    baseURL: 'https://generativelanguage.googleapis.com',
+    models: [
+      { id: 'gemini-2.5-flash', name: 'Gemini 2.5 Flash', recommended: true },
+      { id: 'gemini-2.5-pro', name: 'Gemini 2.5 Pro' }
+    ],
+    defaultModel: 'gemini-2.5-flash',
+    guide: {
+      title: 'providers.gemini.guideTitle',
+      steps: [
+        'providers.gemini.steps.0',
+        'providers.gemini.steps.1',
+        'providers.gemini.steps.2',
+        'providers.gemini.steps.3'
+      ],
+      link: 'https://aistudio.google.com/apikey',
+      pricing: 'providers.gemini.pricing'
+    }
+  },
+  {
+    id: 'azure',
+    name: 'Azure OpenAI',
+    baseURL: '',
+    editableBaseURL: true,
+    models: [],
+    defaultModel: 'gpt-4o-mini',
+    guide: {
+      title: 'providers.azure.guideTitle',
+      steps: [
+        'providers.azure.steps.0',
+        'providers.azure.steps.1',
+        'providers.azure.steps.2',
+        'providers.azure.steps.3'
+      ],
+      link: 'https://portal.azure.com',
+      pricing: 'providers.azure.pricing'
+    }
+  },
+  {
+    id: 'ollama',
+    name: 'Ollama (本地)',
+    baseURL: 'http://localhost:11434',
+    editableBaseURL: true,
+    keyOptional: true,
+    models: [],
+    defaultModel: 'qwen2.5:7b',
+    guide: {
+      title: 'providers.ollama.guideTitle',
+      steps: [
+        'providers.ollama.steps.0',
+        'providers.ollama.steps.1',
+        'providers.ollama.steps.2',
+        'providers.ollama.steps.3'
+      ],
+      link: 'https://ollama.com/download',
+      pricing: 'providers.ollama.pricing'
+    }
+  },
   {
     id: 'custom',
     name: '自定义',
@@ -75,7 +75,11 @@ export default {
     recommended: 'Recommended',
     customProvider: 'Custom Provider',
     baseUrl: 'API URL',
-    baseUrlPlaceholder: 'Enter API URL'
+    baseUrlPlaceholder: 'Enter API URL',
+    deployment: 'Deployment',
+    deploymentPlaceholder: 'Defaults to the model name',
+    apiVersion: 'API version',
+    apiVersionPlaceholder: 'Defaults to 2024-06-01'
   },
 
   providers: {
@@ -149,6 +153,58 @@ export default {
       pricing: 'Some models free, others pay-as-you-go',
       openConsole: 'Open SiliconFlow Console'
     },
+    anthropic: {
+      name: 'Anthropic Claude',
+      description: 'Claude models with strong long-document understanding',
+      guideTitle: 'How to get an Anthropic API Key',
+      steps: [
+        'Visit the Anthropic Console at console.anthropic.com',
+        'Sign up / log in and set up billing',
+        'Open the API Keys page and create a new key',
+        'Copy the key and paste it here'
+      ],
+      pricing: 'Pay as you go, see the Anthropic website',
+      openConsole: 'Open Anthropic Console'
+    },
+    gemini: {
+      name: 'Google Gemini',
+      description: 'Google's Gemini models',
+      guideTitle: 'How to get a Gemini API Key',
+      steps: [
+        'Visit Google AI Studio',
+        'Sign in with your Google account',
+        'Click "Get API key" to create a key',
+        'Copy the key and paste it here'
+      ],
+      pricing: 'Free tier available, then pay as you go',
+      openConsole: 'Open Google AI Studio'
+    },
+    azure: {
+      name: 'Azure OpenAI',
+      description: 'OpenAI models through your Azure subscription',
+      guideTitle: 'How to set up Azure OpenAI',
+      steps: [
+        'Create an Azure OpenAI resource in the Azure portal',
+        'Deploy a model in the resource and note the deployment name',
+        'Copy the endpoint and key from Keys and Endpoint',
+        'Fill in the endpoint, key and deployment name'
+      ],
+      pricing: 'Billed through your Azure subscription',
+      openConsole: 'Open Azure Portal'
+    },
+    ollama: {
+      name: 'Ollama (Local)',
+      description: 'Run open-source models locally, no API key needed',
+      guideTitle: 'How to set up Ollama',
+      steps: [
+        'Download and install Ollama from ollama.com',
+        'Run ollama pull qwen2.5:7b to download a model',
+        'Make sure Ollama is running (default http://localhost:11434)',
+        'Enter the model name; the API key can be left empty'
+      ],
+      pricing: 'Runs locally, free',
+      openConsole: 'Download Ollama'
+    },
     custom: {
       name: 'Custom',
       description: 'Use OpenAI-compatible API',
@@ -195,6 +251,9 @@ export default {
   analysis: {
     concurrent: 'Concurrent',
     concurrentHint: 'simultaneous analyses',
+    rpm: 'Requests per minute (RPM)',
+    tpm: 'Tokens per minute (TPM)',
+    limitHint: '0 = unlimited',
     autoStart: 'Auto-start analysis',
     autoStartDesc: 'Automatically start analysis after adding resumes',
     pending: 'Pending',
@@ -75,7 +75,11 @@ export default {
     recommended: '推荐',
     customProvider: '自定义服务商',
     baseUrl: 'API 地址',
-    baseUrlPlaceholder: '请输入 API 地址'
+    baseUrlPlaceholder: '请输入 API 地址',
+    deployment: '部署名',
+    deploymentPlaceholder: '为空时使用模型名',
+    apiVersion: 'API 版本',
+    apiVersionPlaceholder: '为空时使用 2024-06-01'
   },
 
   providers: {
@@ -149,6 +153,58 @@ export default {
       pricing: '部分模型免费，其他按量计费',
       openConsole: '打开硅基流动控制台'
     },
+    anthropic: {
+      name: 'Anthropic Claude',
+      description: 'Claude 系列模型，长文本理解能力强',
+      guideTitle: '如何获取 Anthropic API Key',
+      steps: [
+        '访问 Anthropic 控制台 console.anthropic.com',
+        '注册/登录账号并完成付费设置',
+        '进入「API Keys」页面创建新的密钥',
+        '复制生成的密钥粘贴到此处'
+      ],
+      pricing: '按量计费，详见 Anthropic 官网',
+      openConsole: '打开 Anthropic 控制台'
+    },
+    gemini: {
+      name: 'Google Gemini',
+      description: 'Google 的 Gemini 系列模型',
+      guideTitle: '如何获取 Gemini API Key',
+      steps: [
+        '访问 Google AI Studio',
+        '使用 Google 账号登录',
+        '点击「Get API key」创建密钥',
+        '复制生成的密钥粘贴到此处'
+      ],
+      pricing: '有免费额度，超出后按量计费',
+      openConsole: '打开 Google AI Studio'
+    },
+    azure: {
+      name: 'Azure OpenAI',
+      description: '通过 Azure 订阅使用 OpenAI 模型',
+      guideTitle: '如何配置 Azure OpenAI',
+      steps: [
+        '在 Azure 门户中创建 Azure OpenAI 资源',
+        '在资源中部署模型，记下部署名',
+        '在「密钥和终结点」页面复制终结点与密钥',
+        '填写终结点、密钥与部署名'
+      ],
+      pricing: '按 Azure 订阅计费',
+      openConsole: '打开 Azure 门户'
+    },
+    ollama: {
+      name: 'Ollama (本地)',
+      description: '在本机运行开源模型，无需 API Key',
+      guideTitle: '如何配置 Ollama',
+      steps: [
+        '从 ollama.com 下载并安装 Ollama',
+        '运行 ollama pull qwen2.5:7b 下载模型',
+        '确认 Ollama 正在运行（默认地址 http://localhost:11434）',
+        '填写模型名称，API 密钥可留空'
+      ],
+      pricing: '本地运行，免费',
+      openConsole: '下载 Ollama'
+    },
     custom: {
       name: '自定义',
       description: '使用 OpenAI 兼容的 API',
@@ -195,6 +251,9 @@ export default {
   analysis: {
     concurrent: '并发数量',
     concurrentHint: '个同时分析',
+    rpm: '每分钟请求数 (RPM)',
+    tpm: '每分钟 Token 数 (TPM)',
+    limitHint: '0 表示不限制',
     autoStart: '自动开始分析',
     autoStartDesc: '添加简历后自动开始分析',
     pending: '待分析',
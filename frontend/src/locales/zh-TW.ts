@@ -75,7 +75,11 @@ export default {
     recommended: '推薦',
     customProvider: '自訂服務商',
     baseUrl: 'API 位址',
-    baseUrlPlaceholder: '請輸入 API 位址'
+    baseUrlPlaceholder: '請輸入 API 位址',
+    deployment: '部署名稱',
+    deploymentPlaceholder: '留空時使用模型名稱',
+    apiVersion: 'API 版本',
+    apiVersionPlaceholder: '留空時使用 2024-06-01'
   },
 
   providers: {
@@ -149,6 +153,58 @@ export default {
       pricing: '部分模型免費，其他按量計費',
       openConsole: '開啟矽基流動控制台'
     },
+    anthropic: {
+      name: 'Anthropic Claude',
+      description: 'Claude 系列模型，長文本理解能力強',
+      guideTitle: '如何取得 Anthropic API Key',
+      steps: [
+        '前往 Anthropic 控制台 console.anthropic.com',
+        '註冊/登入帳號並完成付費設定',
+        '進入「API Keys」頁面建立新的金鑰',
+        '複製產生的金鑰貼上到此處'
+      ],
+      pricing: '按量計費，詳見 Anthropic 官網',
+      openConsole: '開啟 Anthropic 控制台'
+    },
+    gemini: {
+      name: 'Google Gemini',
+      description: 'Google 的 Gemini 系列模型',
+      guideTitle: '如何取得 Gemini API Key',
+      steps: [
+        '前往 Google AI Studio',
+        '使用 Google 帳號登入',
+        '點擊「Get API key」建立金鑰',
+        '複製產生的金鑰貼上到此處'
+      ],
+      pricing: '有免費額度，超出後按量計費',
+      openConsole: '開啟 Google AI Studio'
+    },
+    azure: {
+      name: 'Azure OpenAI',
+      description: '透過 Azure 訂閱使用 OpenAI 模型',
+      guideTitle: '如何設定 Azure OpenAI',
+      steps: [
+        '在 Azure 入口網站建立 Azure OpenAI 資源',
+        '在資源中部署模型，記下部署名稱',
+        '在「金鑰和端點」頁面複製端點與金鑰',
+        '填寫端點、金鑰與部署名稱'
+      ],
+      pricing: '依 Azure 訂閱計費',
+      openConsole: '開啟 Azure 入口網站'
+    },
+    ollama: {
+      name: 'Ollama (本機)',
+      description: '在本機執行開源模型，無需 API Key',
+      guideTitle: '如何設定 Ollama',
+      steps: [
+        '從 ollama.com 下載並安裝 Ollama',
+        '執行 ollama pull qwen2.5:7b 下載模型',
+        '確認 Ollama 正在執行（預設位址 http://localhost:11434）',
+        '填寫模型名稱，API 金鑰可留空'
+      ],
+      pricing: '本機執行，免費',
+      openConsole: '下載 Ollama'
+    },
     custom: {
       name: '自訂',
       description: '使用 OpenAI 相容的 API',
@@ -195,6 +251,9 @@ export default {
   analysis: {
     concurrent: '並行數量',
     concurrentHint: '個同時分析',
+    rpm: '每分鐘請求數 (RPM)',
+    tpm: '每分鐘 Token 數 (TPM)',
+    limitHint: '0 表示不限制',
     autoStart: '自動開始分析',
     autoStartDesc: '新增履歷後自動開始分析',
     pending: '待分析',
@@ -0,0 +1,555 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// ============================================
+// IMAP 收件箱轮询：定期拉取招聘邮箱中的新邮件并导入到项目
+// ============================================
+
+const (
+	defaultIMAPFolder   = "INBOX"
+	defaultIMAPSearch   = "UNSEEN"
+	defaultIMAPFlag     = `\Seen`
+	defaultIMAPInterval = 5  // 分钟
+	imapFetchBatch      = 50 // 每轮最多处理的邮件数
+	imapCommandTimeout  = 60 // 秒，单条命令的读写超时
+	maxIMAPLiteralBytes = 100 << 20
+)
+
+// IMAPConfig IMAP 邮箱配置
+type IMAPConfig struct {
+	Enabled       bool   `json:"enabled"`
+	Host          string `json:"host"`
+	Port          int    `json:"port"`     // 为 0 时按 Security 取 993 / 143
+	Security      string `json:"security"` // tls（默认）/ starttls / none（仅用于本地测试服务器）
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Folder        string `json:"folder"`         // 默认 INBOX
+	Search        string `json:"search"`         // IMAP SEARCH 条件，默认 UNSEEN，如 UNSEEN SUBJECT "简历"
+	ProjectID     string `json:"project_id"`     // 导入到的项目
+	Interval      int    `json:"interval"`       // 轮询间隔（分钟）
+	ProcessedFlag string `json:"processed_flag"` // 处理后给邮件加的标记，默认 \Seen
+	AutoAnalyze   bool   `json:"auto_analyze"`   // 导入后自动分析
+}
+
+// imapCursor 轮询位置：UIDVALIDITY 变化时（邮箱被重建）从头开始
+type imapCursor struct {
+	Account     string `json:"account"` // user@host/folder，配置变更后重新开始
+	UIDValidity uint32 `json:"uid_validity"`
+	LastUID     uint32 `json:"last_uid"`
+}
+
+// imapClient 轮询所需的 IMAP 操作；可替换为本地测试用的实现
+type imapClient interface {
+	Login(username, password string) error
+	Select(folder string) (uidValidity uint32, err error)
+	UIDSearch(criteria string) ([]uint32, error)
+	UIDFetch(uid uint32) ([]byte, error)
+	UIDStoreFlag(uid uint32, flag string) error
+	Logout() error
+}
+
+// imapDialer 建立 IMAP 连接，App.imapDial 为空时使用 dialIMAP
+type imapDialer func(cfg *IMAPConfig) (imapClient, error)
+
+// imapRootCAs 校验服务器证书使用的根证书，为空时使用系统根证书；测试时替换为本地证书
+var imapRootCAs *x509.CertPool
+
+// withIMAPDefaults 补全默认值
+func withIMAPDefaults(cfg IMAPConfig) IMAPConfig {
+	if cfg.Security == "" {
+		cfg.Security = "tls"
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 993
+		if cfg.Security != "tls" {
+			cfg.Port = 143
+		}
+	}
+	if cfg.Folder == "" {
+		cfg.Folder = defaultIMAPFolder
+	}
+	if strings.TrimSpace(cfg.Search) == "" {
+		cfg.Search = defaultIMAPSearch
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultIMAPInterval
+	}
+	if cfg.ProcessedFlag == "" {
+		cfg.ProcessedFlag = defaultIMAPFlag
+	}
+	return cfg
+}
+
+// startIMAPPoller 按当前配置启动轮询（未启用时什么都不做）
+func (a *App) startIMAPPoller() {
+	cfg := withIMAPDefaults(a.currentConfig().IMAP)
+	if !cfg.Enabled || cfg.Host == "" || cfg.ProjectID == "" {
+		return
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.imapMu.Lock()
+	a.imapStop = cancel
+	a.imapMu.Unlock()
+
+	go func() {
+		log.Printf("[IMAP] 开始轮询 %s/%s，间隔 %d 分钟", cfg.Host, cfg.Folder, cfg.Interval)
+		ticker := time.NewTicker(time.Duration(cfg.Interval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			if _, err := a.pollIMAP(ctx, &cfg); err != nil {
+				log.Printf("[IMAP] 轮询失败: %v", err)
+				a.emit("imap:error", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("[IMAP] 停止轮询")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// restartIMAPPoller 配置变更后重新启动轮询
+func (a *App) restartIMAPPoller() {
+	a.imapMu.Lock()
+	if a.imapStop != nil {
+		a.imapStop()
+		a.imapStop = nil
+	}
+	a.imapMu.Unlock()
+	a.startIMAPPoller()
+}
+
+// PollIMAPNow 立即拉取一次（设置页的"立即收取"）
+func (a *App) PollIMAPNow() (*ImportSummary, error) {
+	cfg := withIMAPDefaults(a.currentConfig().IMAP)
+	if cfg.Host == "" || cfg.ProjectID == "" {
+		return nil, fmt.Errorf("IMAP 未配置: 请填写服务器地址并选择导入项目")
+	}
+	return a.pollIMAP(a.ctx, &cfg)
+}
+
+// TestIMAPConnection 测试 IMAP 登录与文件夹是否可用
+func (a *App) TestIMAPConnection(cfg *IMAPConfig) (bool, string) {
+	c := withIMAPDefaults(*cfg)
+	if c.Host == "" {
+		return false, "请填写 IMAP 服务器地址"
+	}
+	client, err := a.dialIMAPClient(&c)
+	if err != nil {
+		return false, "连接失败: " + err.Error()
+	}
+	defer client.Logout()
+	if err := client.Login(c.Username, c.Password); err != nil {
+		return false, "登录失败: " + err.Error()
+	}
+	if _, err := client.Select(c.Folder); err != nil {
+		return false, "打开文件夹失败: " + err.Error()
+	}
+	uids, err := client.UIDSearch(c.Search)
+	if err != nil {
+		return false, "搜索失败: " + err.Error()
+	}
+	return true, fmt.Sprintf("连接成功，%s 中有 %d 封符合条件的邮件", c.Folder, len(uids))
+}
+
+// dialIMAPClient 使用注入的连接方式（测试）或默认的网络连接
+func (a *App) dialIMAPClient(cfg *IMAPConfig) (imapClient, error) {
+	if a.imapDial != nil {
+		return a.imapDial(cfg)
+	}
+	return dialIMAP(cfg)
+}
+
+// pollIMAP 拉取一轮并通知前端，按配置自动分析新导入的简历
+func (a *App) pollIMAP(ctx context.Context, cfg *IMAPConfig) (*ImportSummary, error) {
+	summary, processed, err := a.syncIMAP(ctx, cfg)
+	if processed > 0 {
+		log.Printf("[IMAP] 处理 %d 封邮件: 成功 %d, 跳过 %d, 失败 %d", processed, summary.Accepted, summary.Skipped, summary.Failed)
+		a.emit("imap:imported", summary)
+		if cfg.AutoAnalyze {
+			a.autoAnalyzeImported(ctx, cfg.ProjectID, summary)
+		}
+	}
+	return summary, err
+}
+
+// syncIMAP 只处理 UID 大于游标的邮件，导入后打上处理标记并推进游标，返回处理的邮件数；
+// 定时轮询与"立即收取"共用游标，同一时间只允许一轮，避免重复导入
+func (a *App) syncIMAP(ctx context.Context, cfg *IMAPConfig) (*ImportSummary, int, error) {
+	a.imapPollMu.Lock()
+	defer a.imapPollMu.Unlock()
+
+	client, err := a.dialIMAPClient(cfg)
+	if err != nil {
+		return nil, 0, fmt.Errorf("连接 IMAP 服务器失败: %v", err)
+	}
+	defer client.Logout()
+
+	if err := client.Login(cfg.Username, cfg.Password); err != nil {
+		return nil, 0, fmt.Errorf("IMAP 登录失败: %v", err)
+	}
+	uidValidity, err := client.Select(cfg.Folder)
+	if err != nil {
+		return nil, 0, fmt.Errorf("打开文件夹 %s 失败: %v", cfg.Folder, err)
+	}
+
+	account := fmt.Sprintf("%s@%s/%s", cfg.Username, cfg.Host, cfg.Folder)
+	cursor := a.loadIMAPCursor()
+	if cursor.Account != account || cursor.UIDValidity != uidValidity {
+		log.Printf("[IMAP] 邮箱或 UIDVALIDITY 变化（%d -> %d），从头开始", cursor.UIDValidity, uidValidity)
+		cursor = &imapCursor{Account: account, UIDValidity: uidValidity}
+	}
+
+	criteria := cfg.Search
+	if cursor.LastUID > 0 {
+		criteria = fmt.Sprintf("UID %d:* %s", cursor.LastUID+1, criteria)
+	}
+	uids, err := client.UIDSearch(criteria)
+	if err != nil {
+		return nil, 0, fmt.Errorf("IMAP 搜索失败: %v", err)
+	}
+
+	summary := &ImportSummary{ProjectID: cfg.ProjectID}
+	tmpDir, err := os.MkdirTemp("", "talentlens-imap-")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	processed := 0
+	for _, uid := range uids {
+		// "n:*" 在没有更大 UID 时仍会返回最后一封，需要过滤
+		if uid <= cursor.LastUID {
+			continue
+		}
+		if ctx.Err() != nil || processed >= imapFetchBatch {
+			break
+		}
+		raw, err := client.UIDFetch(uid)
+		if err != nil {
+			// 取信失败时不推进游标，下一轮重试
+			a.saveIMAPCursor(cursor)
+			return summary, processed, fmt.Errorf("获取邮件 UID %d 失败: %v", uid, err)
+		}
+
+		msgPath := filepath.Join(tmpDir, fmt.Sprintf("%d", uid), mboxMessageName(raw, int(uid)))
+		display := fmt.Sprintf("imap://%s/%s;UID=%d", cfg.Host, cfg.Folder, uid)
+		if err := os.MkdirAll(filepath.Dir(msgPath), 0755); err == nil {
+			err = os.WriteFile(msgPath, raw, 0644)
+		}
+		if err != nil {
+			summary.add(ImportItem{Path: display, FileName: filepath.Base(msgPath), Status: ImportFailed, Reason: "保存邮件失败: " + err.Error()})
+		} else {
+			a.importEmail(cfg.ProjectID, msgPath, display, 1, summary)
+		}
+
+		if err := client.UIDStoreFlag(uid, cfg.ProcessedFlag); err != nil {
+			log.Printf("[IMAP] 标记邮件 UID %d 失败: %v", uid, err)
+		}
+		cursor.LastUID = uid
+		a.saveIMAPCursor(cursor)
+		processed++
+	}
+
+	return summary, processed, nil
+}
+
+// imapCursorPath 游标文件
+func (a *App) imapCursorPath() string {
+	return filepath.Join(a.getDataDir(), "imap_cursor.json")
+}
+
+// loadIMAPCursor 读取游标
+func (a *App) loadIMAPCursor() *imapCursor {
+	cursor := &imapCursor{}
+	if data, err := os.ReadFile(a.imapCursorPath()); err == nil {
+		json.Unmarshal(data, cursor)
+	}
+	return cursor
+}
+
+// saveIMAPCursor 保存游标
+func (a *App) saveIMAPCursor(c *imapCursor) {
+	data, _ := json.MarshalIndent(c, "", "  ")
+	os.WriteFile(a.imapCursorPath(), data, 0644)
+}
+
+// ============================================
+// 最小 IMAP4rev1 客户端（仅实现轮询所需命令）
+// ============================================
+
+// imapConn 基于 TCP/TLS 的 IMAP 连接
+type imapConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+	tag  int
+}
+
+// imapResponse 一条服务器响应；行中的字面量 {n} 内容按顺序放在 literals 中
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+// dialIMAP 连接服务器并读取问候语；security 为 starttls 时升级为 TLS
+func dialIMAP(cfg *IMAPConfig) (imapClient, error) {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	dialer := &net.Dialer{Timeout: imapCommandTimeout * time.Second}
+	tlsCfg := &tls.Config{ServerName: cfg.Host, RootCAs: imapRootCAs}
+
+	var conn net.Conn
+	var err error
+	if cfg.Security == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsCfg)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &imapConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+	greeting, err := c.readResponse()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting.line, "* OK") && !strings.HasPrefix(greeting.line, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("服务器拒绝连接: %s", greeting.line)
+	}
+
+	if cfg.Security == "starttls" {
+		if _, err := c.command("STARTTLS"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, tlsCfg)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		c.conn = tlsConn
+		c.r = bufio.NewReader(tlsConn)
+		c.w = bufio.NewWriter(tlsConn)
+	}
+	return c, nil
+}
+
+// readResponse 读取一条响应（含其中的字面量）
+func (c *imapConn) readResponse() (*imapResponse, error) {
+	resp := &imapResponse{}
+	var sb strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		sb.WriteString(line)
+
+		// 行尾 {n} 表示后面紧跟 n 字节的字面量，之后响应继续
+		n, ok := imapLiteralSize(line)
+		if !ok {
+			break
+		}
+		if n > maxIMAPLiteralBytes {
+			return nil, fmt.Errorf("邮件过大（%d 字节）", n)
+		}
+		lit := make([]byte, n)
+		if _, err := io.ReadFull(c.r, lit); err != nil {
+			return nil, err
+		}
+		resp.literals = append(resp.literals, lit)
+	}
+	resp.line = sb.String()
+	return resp, nil
+}
+
+// imapLiteralSize 解析行尾的 {n}
+func imapLiteralSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	i := strings.LastIndex(line, "{")
+	if i == -1 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(line[i+1:len(line)-1], "+"))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// command 发送带标签的命令，返回标签响应之前的所有未标记响应
+func (c *imapConn) command(format string, args ...interface{}) ([]*imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	c.conn.SetDeadline(time.Now().Add(imapCommandTimeout * time.Second))
+	if _, err := fmt.Fprintf(c.w, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	var untagged []*imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(resp.line, tag+" ") {
+			status := strings.TrimPrefix(resp.line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return untagged, fmt.Errorf("%s", status)
+			}
+			return untagged, nil
+		}
+		untagged = append(untagged, resp)
+	}
+}
+
+// Login 明文登录（连接本身已加密）
+func (c *imapConn) Login(username, password string) error {
+	_, err := c.command("LOGIN %s %s", imapQuote(username), imapQuote(password))
+	return err
+}
+
+// Select 打开文件夹并返回 UIDVALIDITY
+func (c *imapConn) Select(folder string) (uint32, error) {
+	resps, err := c.command("SELECT %s", imapQuote(encodeIMAPFolder(folder)))
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range resps {
+		if i := strings.Index(r.line, "[UIDVALIDITY "); i != -1 {
+			rest := r.line[i+len("[UIDVALIDITY "):]
+			if j := strings.Index(rest, "]"); j != -1 {
+				v, err := strconv.ParseUint(rest[:j], 10, 32)
+				if err == nil {
+					return uint32(v), nil
+				}
+			}
+		}
+	}
+	return 0, fmt.Errorf("服务器未返回 UIDVALIDITY")
+}
+
+// UIDSearch 按条件搜索，返回 UID 列表
+func (c *imapConn) UIDSearch(criteria string) ([]uint32, error) {
+	// 条件中含中文时需要声明字符集
+	prefix := ""
+	for _, r := range criteria {
+		if r > 0x7e {
+			prefix = "CHARSET UTF-8 "
+			break
+		}
+	}
+	resps, err := c.command("UID SEARCH %s%s", prefix, criteria)
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, r := range resps {
+		if !strings.HasPrefix(r.line, "* SEARCH") {
+			continue
+		}
+		for _, f := range strings.Fields(strings.TrimPrefix(r.line, "* SEARCH")) {
+			if v, err := strconv.ParseUint(f, 10, 32); err == nil {
+				uids = append(uids, uint32(v))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// UIDFetch 获取整封邮件原文（BODY.PEEK 不会自动设置 \Seen）
+func (c *imapConn) UIDFetch(uid uint32) ([]byte, error) {
+	resps, err := c.command("UID FETCH %d (BODY.PEEK[])", uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resps {
+		if strings.Contains(r.line, "FETCH") && len(r.literals) > 0 {
+			return r.literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("邮件不存在")
+}
+
+// UIDStoreFlag 添加标记
+func (c *imapConn) UIDStoreFlag(uid uint32, flag string) error {
+	_, err := c.command("UID STORE %d +FLAGS.SILENT (%s)", uid, flag)
+	return err
+}
+
+// Logout 退出并关闭连接
+func (c *imapConn) Logout() error {
+	c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// imapQuote 把字符串编码为 IMAP 引号字符串
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// encodeIMAPFolder 把文件夹名编码为 IMAP 修改版 UTF-7（RFC 3501 5.1.3），如"招聘" -> "&YtuAWA-"
+func encodeIMAPFolder(name string) string {
+	var sb strings.Builder
+	var pending []rune
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		units := utf16.Encode(pending)
+		buf := make([]byte, 0, len(units)*2)
+		for _, u := range units {
+			buf = append(buf, byte(u>>8), byte(u))
+		}
+		enc := base64.RawStdEncoding.EncodeToString(buf)
+		sb.WriteString("&" + strings.ReplaceAll(enc, "/", ",") + "-")
+		pending = nil
+	}
+	for _, r := range name {
+		switch {
+		case r == '&':
+			flush()
+			sb.WriteString("&-")
+		case r >= 0x20 && r <= 0x7e:
+			flush()
+			sb.WriteRune(r)
+		default:
+			pending = append(pending, r)
+		}
+	}
+	flush()
+	return sb.String()
+}
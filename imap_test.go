@@ -0,0 +1,449 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeIMAP 本地 IMAP 替身：记录搜索条件与打上的标记
+type fakeIMAP struct {
+	mu          sync.Mutex
+	uidValidity uint32
+	messages    map[uint32][]byte
+	flags       map[uint32][]string
+	searches    []string
+	fetches     []uint32
+}
+
+func newFakeIMAP(uidValidity uint32, uids ...uint32) *fakeIMAP {
+	f := &fakeIMAP{uidValidity: uidValidity, messages: map[uint32][]byte{}, flags: map[uint32][]string{}}
+	for _, uid := range uids {
+		f.add(uid)
+	}
+	return f
+}
+
+// add 添加一封没有附件、正文为空的邮件（导入时跳过，不产生简历）
+func (f *fakeIMAP) add(uid uint32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages[uid] = []byte(fmt.Sprintf("From: hr@example.com\r\nMessage-ID: <%d@example.com>\r\n\r\n", uid))
+}
+
+func (f *fakeIMAP) Login(username, password string) error { return nil }
+
+func (f *fakeIMAP) Select(folder string) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.uidValidity, nil
+}
+
+// UIDSearch 只解析 "UID n:*" 前缀；与真实服务器一样，没有更大的 UID 时返回最后一封
+func (f *fakeIMAP) UIDSearch(criteria string) ([]uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.searches = append(f.searches, criteria)
+	var from uint32
+	fmt.Sscanf(criteria, "UID %d:*", &from)
+	var all, uids []uint32
+	for uid := range f.messages {
+		all = append(all, uid)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	for _, uid := range all {
+		if uid >= from {
+			uids = append(uids, uid)
+		}
+	}
+	if len(uids) == 0 && len(all) > 0 {
+		uids = all[len(all)-1:]
+	}
+	return uids, nil
+}
+
+func (f *fakeIMAP) UIDFetch(uid uint32) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches = append(f.fetches, uid)
+	return f.messages[uid], nil
+}
+
+func (f *fakeIMAP) UIDStoreFlag(uid uint32, flag string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags[uid] = append(f.flags[uid], flag)
+	return nil
+}
+
+func (f *fakeIMAP) Logout() error { return nil }
+
+func newIMAPTestApp(t *testing.T, server *fakeIMAP) (*App, *IMAPConfig) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	a := &App{imapDial: func(*IMAPConfig) (imapClient, error) { return server, nil }}
+	cfg := withIMAPDefaults(IMAPConfig{Host: "imap.test", Username: "hr"})
+	return a, &cfg
+}
+
+func TestSyncIMAPCursor(t *testing.T) {
+	server := newFakeIMAP(100, 1, 2)
+	a, cfg := newIMAPTestApp(t, server)
+	ctx := context.Background()
+
+	if _, n, err := a.syncIMAP(ctx, cfg); err != nil || n != 2 {
+		t.Fatalf("第一轮应处理 2 封，实际 %d, err=%v", n, err)
+	}
+	if got := a.loadIMAPCursor(); got.LastUID != 2 || got.UIDValidity != 100 {
+		t.Fatalf("游标错误: %+v", got)
+	}
+	for _, uid := range []uint32{1, 2} {
+		if flags := server.flags[uid]; len(flags) != 1 || flags[0] != defaultIMAPFlag {
+			t.Errorf("UID %d 应打上 %s 标记，实际 %v", uid, defaultIMAPFlag, flags)
+		}
+	}
+
+	// 只处理游标之后的新邮件
+	server.add(3)
+	if _, n, err := a.syncIMAP(ctx, cfg); err != nil || n != 1 {
+		t.Fatalf("第二轮应只处理 1 封，实际 %d, err=%v", n, err)
+	}
+	if last := server.searches[len(server.searches)-1]; !strings.HasPrefix(last, "UID 3:* ") {
+		t.Errorf("搜索条件应从游标之后开始，实际 %q", last)
+	}
+
+	// 没有新邮件时服务器仍返回最后一封，不应重复处理
+	if _, n, _ := a.syncIMAP(ctx, cfg); n != 0 {
+		t.Fatalf("没有新邮件时不应处理，实际 %d", n)
+	}
+	if len(server.fetches) != 3 {
+		t.Errorf("应共取信 3 次，实际 %v", server.fetches)
+	}
+}
+
+func TestSyncIMAPUIDValidityReset(t *testing.T) {
+	server := newFakeIMAP(100, 5, 6)
+	a, cfg := newIMAPTestApp(t, server)
+	ctx := context.Background()
+	if _, n, _ := a.syncIMAP(ctx, cfg); n != 2 {
+		t.Fatalf("应处理 2 封，实际 %d", n)
+	}
+
+	// 邮箱重建：UID 重新编号，游标从头开始
+	server.mu.Lock()
+	server.uidValidity = 200
+	server.messages = map[uint32][]byte{}
+	server.mu.Unlock()
+	server.add(1)
+	if _, n, err := a.syncIMAP(ctx, cfg); err != nil || n != 1 {
+		t.Fatalf("UIDVALIDITY 变化后应重新处理，实际 %d, err=%v", n, err)
+	}
+	if got := a.loadIMAPCursor(); got.UIDValidity != 200 || got.LastUID != 1 {
+		t.Fatalf("游标应重置，实际 %+v", got)
+	}
+	if last := server.searches[len(server.searches)-1]; last != cfg.Search {
+		t.Errorf("重置后应不带 UID 范围搜索，实际 %q", last)
+	}
+}
+
+func TestSyncIMAPConcurrentPolls(t *testing.T) {
+	server := newFakeIMAP(100, 1, 2, 3, 4)
+	a, cfg := newIMAPTestApp(t, server)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	total := 0
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, n, _ := a.syncIMAP(context.Background(), cfg)
+			mu.Lock()
+			total += n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if total != 4 || len(server.fetches) != 4 {
+		t.Fatalf("并发轮询不应重复处理: 共处理 %d 封, 取信 %v", total, server.fetches)
+	}
+}
+
+// scriptedIMAP 在本地端口上监听的 IMAP 服务器：按脚本应答 dialIMAP 发出的命令，记录收到的命令
+type scriptedIMAP struct {
+	t           *testing.T
+	ln          net.Listener
+	tlsCfg      *tls.Config
+	uidValidity uint32
+	messages    map[uint32][]byte
+
+	mu       sync.Mutex
+	commands []string // 去掉标签的命令
+	plain    []string // STARTTLS 之前以明文收到的命令
+}
+
+func newScriptedIMAP(t *testing.T, uidValidity uint32, messages map[uint32][]byte) *scriptedIMAP {
+	t.Helper()
+	// 借用 httptest 生成的 127.0.0.1 自签名证书
+	ts := httptest.NewTLSServer(nil)
+	cert := ts.TLS.Certificates[0]
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	ts.Close()
+
+	old := imapRootCAs
+	imapRootCAs = roots
+	t.Cleanup(func() { imapRootCAs = old })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &scriptedIMAP{
+		t:           t,
+		ln:          ln,
+		tlsCfg:      &tls.Config{Certificates: []tls.Certificate{cert}},
+		uidValidity: uidValidity,
+		messages:    messages,
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *scriptedIMAP) port() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *scriptedIMAP) record(cmd string, secure bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = append(s.commands, cmd)
+	if !secure {
+		s.plain = append(s.plain, cmd)
+	}
+}
+
+// received 返回以 prefix 开头的命令
+func (s *scriptedIMAP) received(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, c := range s.commands {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (s *scriptedIMAP) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(w, format, args...)
+		w.Flush()
+	}
+	reply("* OK [CAPABILITY IMAP4rev1 STARTTLS] scripted server ready\r\n")
+
+	secure := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		s.record(cmd, secure)
+		verb := strings.ToUpper(strings.Fields(cmd)[0])
+		if verb == "UID" {
+			verb += " " + strings.ToUpper(strings.Fields(cmd)[1])
+		}
+
+		switch verb {
+		case "STARTTLS":
+			reply("%s OK Begin TLS negotiation now\r\n", tag)
+			tlsConn := tls.Server(conn, s.tlsCfg)
+			if err := tlsConn.Handshake(); err != nil {
+				s.t.Errorf("TLS 握手失败: %v", err)
+				return
+			}
+			conn, secure = tlsConn, true
+			r, w = bufio.NewReader(conn), bufio.NewWriter(conn)
+		case "LOGIN":
+			reply("%s OK LOGIN completed\r\n", tag)
+		case "SELECT":
+			reply("* %d EXISTS\r\n* FLAGS (\\Seen \\Flagged)\r\n* OK [UIDVALIDITY %d] UIDs valid\r\n* OK [UIDNEXT 100] Predicted next UID\r\n%s OK [READ-WRITE] SELECT completed\r\n",
+				len(s.messages), s.uidValidity, tag)
+		case "UID SEARCH":
+			// 与真实服务器一样不按游标过滤，"n:*" 的语义由客户端处理
+			var uids []string
+			for uid := range s.messages {
+				uids = append(uids, fmt.Sprint(uid))
+			}
+			sort.Strings(uids)
+			reply("* SEARCH %s\r\n%s OK SEARCH completed\r\n", strings.Join(uids, " "), tag)
+		case "UID FETCH":
+			var uid uint32
+			fmt.Sscanf(cmd, "UID FETCH %d", &uid)
+			raw, ok := s.messages[uid]
+			if !ok {
+				reply("%s OK FETCH completed\r\n", tag)
+				continue
+			}
+			// 字面量之后同一响应中还有其他数据项
+			reply("* 1 FETCH (UID %d BODY[] {%d}\r\n%s FLAGS ())\r\n%s OK FETCH completed\r\n", uid, len(raw), raw, tag)
+		case "UID STORE":
+			reply("%s OK STORE completed\r\n", tag)
+		case "LOGOUT":
+			reply("* BYE logging out\r\n%s OK LOGOUT completed\r\n", tag)
+			return
+		default:
+			reply("%s BAD unknown command\r\n", tag)
+		}
+	}
+}
+
+// buildTestDOCX 生成只有一个段落的最小 .docx
+func buildTestDOCX(t *testing.T, text string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="xml" ContentType="application/xml"/><Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`,
+		"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="` + docxWordNS + `"><w:body><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:body></w:document>`,
+	}
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// buildTestEmail 生成带 .docx 附件的 multipart 邮件
+func buildTestEmail(docx []byte) []byte {
+	enc := base64.StdEncoding.EncodeToString(docx)
+	var body strings.Builder
+	for len(enc) > 76 {
+		body.WriteString(enc[:76] + "\r\n")
+		enc = enc[76:]
+	}
+	body.WriteString(enc + "\r\n")
+
+	return []byte("From: =?UTF-8?B?" + base64.StdEncoding.EncodeToString([]byte("张三")) + "?= <zhangsan@example.com>\r\n" +
+		"To: hr@example.com\r\n" +
+		"Subject: =?UTF-8?B?" + base64.StdEncoding.EncodeToString([]byte("应聘 Go 工程师 - 张三")) + "?=\r\n" +
+		"Message-ID: <resume-7@example.com>\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b1\"\r\n" +
+		"\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"您好，附件是我的简历。\r\n" +
+		"--b1\r\n" +
+		"Content-Type: application/vnd.openxmlformats-officedocument.wordprocessingml.document; name=\"zhangsan.docx\"\r\n" +
+		"Content-Disposition: attachment; filename=\"zhangsan.docx\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		body.String() +
+		"--b1--\r\n")
+}
+
+func TestSyncIMAPScriptedServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	const cv = "张三 高级 Go 工程师 电话 13800138000 邮箱 zhangsan@example.com 五年分布式系统开发经验，熟悉 Kubernetes 与 PostgreSQL"
+	server := newScriptedIMAP(t, 7, map[uint32][]byte{7: buildTestEmail(buildTestDOCX(t, cv))})
+
+	a := &App{}
+	p := a.CreateProject("后端工程师", &JobConfig{})
+	cfg := withIMAPDefaults(IMAPConfig{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		Security:  "starttls",
+		Username:  "hr",
+		Password:  `p"w`,
+		Folder:    "招聘",
+		Search:    `UNSEEN SUBJECT "简历"`,
+		ProjectID: p.ID,
+	})
+
+	summary, n, err := a.syncIMAP(context.Background(), &cfg)
+	if err != nil || n != 1 {
+		t.Fatalf("应处理 1 封邮件，实际 %d, err=%v", n, err)
+	}
+	if summary.Accepted != 1 {
+		t.Fatalf("附件应导入为简历: %+v", summary.Items)
+	}
+
+	// 协议细节：STARTTLS 之后才发送密码，文件夹名按修改版 UTF-7 编码，中文搜索条件声明字符集
+	server.mu.Lock()
+	plain := append([]string(nil), server.plain...)
+	server.mu.Unlock()
+	if len(plain) != 1 || plain[0] != "STARTTLS" {
+		t.Errorf("STARTTLS 之前只应发送 STARTTLS，实际 %q", plain)
+	}
+	if got := server.received("LOGIN"); len(got) != 1 || got[0] != `LOGIN "hr" "p\"w"` {
+		t.Errorf("LOGIN 命令错误: %q", got)
+	}
+	if got := server.received("SELECT"); len(got) != 1 || got[0] != `SELECT "&YtuAWA-"` {
+		t.Errorf("SELECT 命令错误: %q", got)
+	}
+	if got := server.received("UID SEARCH"); len(got) != 1 || got[0] != `UID SEARCH CHARSET UTF-8 UNSEEN SUBJECT "简历"` {
+		t.Errorf("SEARCH 命令错误: %q", got)
+	}
+	if got := server.received("UID STORE"); len(got) != 1 || got[0] != `UID STORE 7 +FLAGS.SILENT (\Seen)` {
+		t.Errorf("应给邮件打上处理标记，实际 %q", got)
+	}
+	if got := a.loadIMAPCursor(); got.UIDValidity != 7 || got.LastUID != 7 {
+		t.Errorf("游标错误: %+v", got)
+	}
+
+	project := a.GetProject(p.ID)
+	if project == nil || len(project.ResumeIDs) != 1 {
+		t.Fatalf("项目中应有 1 份简历: %+v", project)
+	}
+	r := a.loadResume(project.ResumeIDs[0])
+	if r == nil || r.FileName != "zhangsan.docx" {
+		t.Fatalf("简历应来自附件: %+v", r)
+	}
+	if !strings.Contains(r.Content, "13800138000") {
+		t.Errorf("应提取附件文本，实际 %q", r.Content)
+	}
+	if r.Email == nil || r.Email.Subject != "应聘 Go 工程师 - 张三" || r.Email.From != "zhangsan@example.com" {
+		t.Errorf("应记录来源邮件: %+v", r.Email)
+	}
+
+	// 服务器仍返回已处理的邮件，游标之前的不再取信
+	if _, n, err := a.syncIMAP(context.Background(), &cfg); err != nil || n != 0 {
+		t.Fatalf("第二轮不应处理邮件，实际 %d, err=%v", n, err)
+	}
+	if got := server.received("UID SEARCH"); len(got) != 2 || !strings.HasPrefix(got[1], "UID SEARCH CHARSET UTF-8 UID 8:* ") {
+		t.Errorf("第二轮搜索应从游标之后开始: %q", got)
+	}
+	if got := server.received("UID FETCH"); len(got) != 1 {
+		t.Errorf("应只取信 1 次，实际 %q", got)
+	}
+}
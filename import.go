@@ -0,0 +1,337 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// ============================================
+// 批量导入：文件、文件夹（递归）、ZIP 压缩包
+// ============================================
+
+const (
+	maxImportFileBytes = 50 << 20 // 单个简历文件大小上限
+	maxImportDepth     = 3        // 压缩包/邮件嵌套的最大层数（如 压缩包 -> 邮件 -> 附件）
+)
+
+// importContainerExts 需要拆开后逐个导入的文件类型
+var importContainerExts = map[string]bool{".zip": true, ".mbox": true, ".mbx": true}
+
+// isImportableExt 可以导入的扩展名（有提取器的简历文件或容器）
+func (a *App) isImportableExt(ext string) bool {
+	return importContainerExts[ext] || a.extractors().supportsExtension(ext)
+}
+
+// 导入结果状态
+const (
+	ImportAccepted = "accepted"
+	ImportSkipped  = "skipped"
+	ImportFailed   = "failed"
+)
+
+// ImportItem 单个文件的导入结果
+type ImportItem struct {
+	Path     string `json:"path"`                // 文件路径；压缩包内的文件为"压缩包路径!/包内路径"
+	FileName string `json:"file_name"`           // 文件名
+	Status   string `json:"status"`              // accepted / skipped / failed
+	Reason   string `json:"reason,omitempty"`    // 跳过或失败原因，导入成功但提取有问题时为提示
+	ResumeID string `json:"resume_id,omitempty"` // 导入成功时的简历 ID
+}
+
+// ImportSummary 一次导入操作的汇总，通过 import:summary 事件发送给前端
+type ImportSummary struct {
+	ProjectID string       `json:"project_id"`
+	Accepted  int          `json:"accepted"`
+	Skipped   int          `json:"skipped"`
+	Failed    int          `json:"failed"`
+	Items     []ImportItem `json:"items"`
+
+	dups *duplicateIndex // 整批共用的查重索引，第一次导入文件时建立
+}
+
+// add 记录一个文件的结果
+func (s *ImportSummary) add(item ImportItem) {
+	switch item.Status {
+	case ImportAccepted:
+		s.Accepted++
+	case ImportSkipped:
+		s.Skipped++
+	default:
+		s.Failed++
+	}
+	s.Items = append(s.Items, item)
+}
+
+// duplicates 本次导入的查重索引，整批只读取一次已有简历
+func (s *ImportSummary) duplicates(a *App) *duplicateIndex {
+	if s.dups == nil {
+		s.dups = a.newDuplicateIndex()
+	}
+	return s.dups
+}
+
+// importPaths 导入一组路径（文件、文件夹或 .zip），逐个通知 resume:dropped，最后发送 import:summary
+func (a *App) importPaths(projectID string, paths []string) *ImportSummary {
+	summary := &ImportSummary{ProjectID: projectID}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			summary.add(ImportItem{Path: p, FileName: filepath.Base(p), Status: ImportFailed, Reason: "无法读取: " + err.Error()})
+			continue
+		}
+		if info.IsDir() {
+			a.importDir(projectID, p, summary)
+			continue
+		}
+		a.importAny(projectID, p, p, 1, summary)
+	}
+
+	log.Printf("[importPaths] 导入完成: 成功 %d, 跳过 %d, 失败 %d", summary.Accepted, summary.Skipped, summary.Failed)
+	a.emit("import:summary", summary)
+	return summary
+}
+
+// importDir 递归导入文件夹，忽略隐藏文件与目录
+func (a *App) importDir(projectID, dir string, summary *ImportSummary) {
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			summary.add(ImportItem{Path: p, FileName: filepath.Base(p), Status: ImportFailed, Reason: "无法读取: " + err.Error()})
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if p != dir && isHiddenImportName(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		a.importAny(projectID, p, p, 1, summary)
+		return nil
+	})
+}
+
+// importAny 按扩展名导入单个文件：压缩包、mbox、邮件拆开处理，其余作为简历文件
+func (a *App) importAny(projectID, filePath, displayPath string, depth int, summary *ImportSummary) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if (importContainerExts[ext] || ext == ".eml") && depth > maxImportDepth {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportSkipped, Reason: "压缩包/邮件嵌套层数过多"})
+		return
+	}
+	switch ext {
+	case ".zip":
+		a.importZip(projectID, filePath, displayPath, depth, summary)
+	case ".mbox", ".mbx":
+		a.importMbox(projectID, filePath, displayPath, depth, summary)
+	case ".eml":
+		a.importEmail(projectID, filePath, displayPath, depth, summary)
+	default:
+		summary.add(a.importFile(projectID, filePath, displayPath, summary.duplicates(a)))
+	}
+}
+
+// importZip 解压到临时目录后逐个导入；简历记录指向托管副本，临时文件在导入后删除
+func (a *App) importZip(projectID, zipPath, displayPath string, depth int, summary *ImportSummary) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: "无法打开压缩包: " + err.Error()})
+		return
+	}
+	defer zr.Close()
+
+	tmpDir, err := os.MkdirTemp("", "talentlens-import-")
+	if err != nil {
+		summary.add(ImportItem{Path: displayPath, FileName: filepath.Base(displayPath), Status: ImportFailed, Reason: "创建临时目录失败: " + err.Error()})
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for i, f := range zr.File {
+		name := zipEntryName(f)
+		item := ImportItem{Path: displayPath + "!/" + name, FileName: filepath.Base(name)}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if isHiddenZipEntry(name) {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(name))
+		switch {
+		case !a.isImportableExt(ext):
+			item.Status, item.Reason = ImportSkipped, "不支持的文件类型: "+ext
+			summary.add(item)
+			continue
+		case f.UncompressedSize64 > maxImportFileBytes:
+			item.Status, item.Reason = ImportSkipped, fmt.Sprintf("文件过大（%d MB）", f.UncompressedSize64>>20)
+			summary.add(item)
+			continue
+		}
+
+		// 每个条目放在独立的子目录中，保留原文件名且不会因包内同名文件互相覆盖；
+		// 只取文件名部分，避免 "../" 之类的路径跳出临时目录
+		dst := filepath.Join(tmpDir, fmt.Sprintf("%d", i), filepath.Base(name))
+		if err := extractZipEntry(f, dst); err != nil {
+			item.Status, item.Reason = ImportFailed, "解压失败: "+err.Error()
+			summary.add(item)
+			continue
+		}
+
+		a.importAny(projectID, dst, item.Path, depth+1, summary)
+	}
+}
+
+// extractZipEntry 把压缩包中的一个文件写到 dst，超过大小上限时报错（防止压缩炸弹）
+func extractZipEntry(f *zip.File, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(rc, maxImportFileBytes+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n > maxImportFileBytes {
+		return fmt.Errorf("解压后超过 %d MB", maxImportFileBytes>>20)
+	}
+	return nil
+}
+
+// zipEntryName 解码压缩包中的文件名：设置了 UTF-8 标志或本身是合法 UTF-8 时直接使用，
+// 否则按 GBK 解码（Windows 中文系统自带压缩工具生成的压缩包）
+func zipEntryName(f *zip.File) string {
+	const utf8Flag = 0x800
+	if f.Flags&utf8Flag != 0 || utf8.ValidString(f.Name) {
+		return f.Name
+	}
+	if name, err := simplifiedchinese.GBK.NewDecoder().String(f.Name); err == nil {
+		return name
+	}
+	return f.Name
+}
+
+// isHiddenImportName 隐藏文件、Office 临时文件（~$开头）不导入
+func isHiddenImportName(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~$") || name == "__MACOSX"
+}
+
+// isHiddenZipEntry macOS 压缩时附带的 __MACOSX 资源文件及隐藏文件
+func isHiddenZipEntry(name string) bool {
+	for _, part := range strings.Split(strings.ReplaceAll(name, "\\", "/"), "/") {
+		if part != "" && isHiddenImportName(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// importFile 导入单个文件；displayPath 用于汇总中显示（压缩包内的文件显示包内路径）
+func (a *App) importFile(projectID, filePath, displayPath string, dups *duplicateIndex) ImportItem {
+	fileName := filepath.Base(filePath)
+	item := ImportItem{Path: displayPath, FileName: fileName}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	extractor, _ := a.extractors().detect(filePath)
+	if extractor == nil {
+		item.Status, item.Reason = ImportSkipped, "不支持的文件类型: "+ext
+		return item
+	}
+	if exts := extractor.Extensions(); len(exts) > 0 && !containsFold(exts, ext) {
+		// 没有扩展名或扩展名与内容不符，按内容识别出的格式记录
+		ext = exts[0]
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		item.Status, item.Reason = ImportFailed, "无法读取: "+err.Error()
+		return item
+	}
+	if info.Size() > maxImportFileBytes {
+		item.Status, item.Reason = ImportSkipped, fmt.Sprintf("文件过大（%d MB）", info.Size()>>20)
+		return item
+	}
+
+	id := fmt.Sprintf("%d_%s", time.Now().UnixNano(), fileName)
+	ok, msg := a.registerResume(projectID, id, fileName, filePath, ext, info.Size(), dups)
+	if !ok {
+		item.Status, item.Reason = ImportSkipped, msg
+		return item
+	}
+
+	resume := a.loadResume(id)
+	if resume == nil {
+		item.Status, item.Reason = ImportFailed, "保存简历失败"
+		return item
+	}
+	item.Status, item.ResumeID = ImportAccepted, id
+	switch {
+	case resume.ExtractError != "":
+		item.Reason = "文本提取失败: " + resume.ExtractError
+	case resume.Status == "duplicate" && resume.Duplicate != nil:
+		item.Reason = "疑似重复，待确认: " + resume.Duplicate.FileName
+	}
+	a.emit("resume:dropped", resume)
+	return item
+}
+
+// SelectResumeFolder 选择文件夹并递归导入其中的简历，返回导入数量
+func (a *App) SelectResumeFolder(projectID string) int {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择简历文件夹",
+	})
+	if err != nil || dir == "" {
+		return 0
+	}
+	return a.importPaths(projectID, []string{dir}).Accepted
+}
+
+// resumeFileFilters 由提取器注册表生成文件对话框的过滤器
+func (a *App) resumeFileFilters() []runtime.FileFilter {
+	reg := a.extractors()
+	var all []string
+	for _, ext := range reg.extensions() {
+		all = append(all, "*"+ext)
+	}
+	all = append(all, "*.mbox", "*.mbx", "*.zip")
+
+	filters := []runtime.FileFilter{{DisplayName: "所有支持的简历文件", Pattern: strings.Join(all, ";")}}
+	labels, exts := reg.groups()
+	for _, label := range labels {
+		var patterns []string
+		for _, ext := range exts[label] {
+			patterns = append(patterns, "*"+ext)
+		}
+		filters = append(filters, runtime.FileFilter{DisplayName: label, Pattern: strings.Join(patterns, ";")})
+	}
+	return append(filters,
+		runtime.FileFilter{DisplayName: "邮箱导出 (MBOX)", Pattern: "*.mbox;*.mbx"},
+		runtime.FileFilter{DisplayName: "ZIP 压缩包", Pattern: "*.zip"},
+		runtime.FileFilter{DisplayName: "所有文件", Pattern: "*.*"},
+	)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ============================================
+// 收件箱文件夹：轮询项目绑定的目录，自动导入新出现的简历
+// 轮询而不是系统文件通知，网络共享/同步盘上同样可用
+// ============================================
+
+const (
+	inboxPollInterval = 5 * time.Second
+	inboxSettleTime   = 3 * time.Second // 文件大小与修改时间保持不变这么久才认为已写完
+)
+
+// InboxConfig 项目的收件箱文件夹配置
+type InboxConfig struct {
+	Dir         string `json:"dir"`
+	Enabled     bool   `json:"enabled"`
+	Recursive   bool   `json:"recursive"`    // 是否包含子文件夹
+	AutoAnalyze bool   `json:"auto_analyze"` // 导入后自动使用当前 AI 配置分析
+}
+
+// inboxStamp 文件的大小与修改时间，用于判断文件是否已导入、是否仍在写入
+type inboxStamp struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// inboxPending 等待写完的文件
+type inboxPending struct {
+	stamp inboxStamp
+	since time.Time
+}
+
+// inboxState 收件箱的导入记录（相对路径 -> 导入时的文件状态），重启后不会重复导入
+type inboxState struct {
+	Dir      string                `json:"dir"`
+	Imported map[string]inboxStamp `json:"imported"`
+}
+
+// startInboxWatchers 启动时为所有启用了收件箱的项目开始轮询
+func (a *App) startInboxWatchers() {
+	for _, p := range a.GetProjects() {
+		if p.Inbox != nil && p.Inbox.Enabled {
+			a.startInbox(p.ID, *p.Inbox)
+		}
+	}
+}
+
+// SetProjectInbox 设置项目的收件箱文件夹；Enabled 为 false 时停止轮询
+func (a *App) SetProjectInbox(projectID string, cfg InboxConfig) error {
+	if cfg.Enabled {
+		info, err := os.Stat(cfg.Dir)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("文件夹不存在: %s", cfg.Dir)
+		}
+	}
+	if a.modifyProject(projectID, func(p *Project) { p.Inbox = &cfg }) == nil {
+		return fmt.Errorf("项目不存在")
+	}
+
+	a.stopInbox(projectID)
+	if cfg.Enabled {
+		a.startInbox(projectID, cfg)
+	}
+	log.Printf("[SetProjectInbox] 项目 %s 收件箱: %s, 启用=%v", projectID, cfg.Dir, cfg.Enabled)
+	return nil
+}
+
+// SelectInboxFolder 打开文件夹选择对话框，返回选择的路径
+func (a *App) SelectInboxFolder() string {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择收件箱文件夹",
+	})
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// startInbox 启动单个项目的轮询协程
+func (a *App) startInbox(projectID string, cfg InboxConfig) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.inboxMu.Lock()
+	if a.inboxStops == nil {
+		a.inboxStops = map[string]context.CancelFunc{}
+	}
+	a.inboxStops[projectID] = cancel
+	a.inboxMu.Unlock()
+
+	go a.watchInbox(ctx, projectID, cfg)
+}
+
+// stopInbox 停止项目的轮询
+func (a *App) stopInbox(projectID string) {
+	a.inboxMu.Lock()
+	defer a.inboxMu.Unlock()
+	if cancel, ok := a.inboxStops[projectID]; ok {
+		cancel()
+		delete(a.inboxStops, projectID)
+	}
+}
+
+// watchInbox 轮询循环
+func (a *App) watchInbox(ctx context.Context, projectID string, cfg InboxConfig) {
+	log.Printf("[watchInbox] 开始监听: 项目=%s, 目录=%s", projectID, cfg.Dir)
+	state := a.loadInboxState(projectID, cfg.Dir)
+	pending := map[string]inboxPending{}
+
+	ticker := time.NewTicker(inboxPollInterval)
+	defer ticker.Stop()
+	for {
+		a.pollInbox(ctx, projectID, cfg, state, pending)
+		select {
+		case <-ctx.Done():
+			log.Printf("[watchInbox] 停止监听: 项目=%s", projectID)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollInbox 扫描一次目录，导入已写完且未导入过的文件
+func (a *App) pollInbox(ctx context.Context, projectID string, cfg InboxConfig, state *inboxState, pending map[string]inboxPending) {
+	now := time.Now()
+	var ready []string
+	seen := map[string]bool{}
+
+	filepath.WalkDir(cfg.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if p != cfg.Dir && isHiddenImportName(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if p != cfg.Dir && !cfg.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(p))
+		if !a.isImportableExt(ext) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(cfg.Dir, p)
+		stamp := inboxStamp{Size: info.Size(), ModTime: info.ModTime()}
+		seen[rel] = true
+		if old, ok := state.Imported[rel]; ok && old.Size == stamp.Size && old.ModTime.Equal(stamp.ModTime) {
+			return nil
+		}
+		// 防抖：文件还在复制/同步中时大小或修改时间会变化，等稳定后再导入
+		if pd, ok := pending[rel]; ok && pd.stamp.Size == stamp.Size && pd.stamp.ModTime.Equal(stamp.ModTime) {
+			if now.Sub(pd.since) >= inboxSettleTime && stamp.Size > 0 {
+				ready = append(ready, rel)
+			}
+			return nil
+		}
+		pending[rel] = inboxPending{stamp: stamp, since: now}
+		return nil
+	})
+
+	// 已被删除的文件不再等待
+	for rel := range pending {
+		if !seen[rel] {
+			delete(pending, rel)
+		}
+	}
+	if len(ready) == 0 {
+		return
+	}
+
+	summary := &ImportSummary{ProjectID: projectID}
+	for _, rel := range ready {
+		if ctx.Err() != nil {
+			return
+		}
+		p := filepath.Join(cfg.Dir, rel)
+		a.importAny(projectID, p, p, 1, summary)
+		state.Imported[rel] = pending[rel].stamp
+		delete(pending, rel)
+	}
+	a.saveInboxState(projectID, state)
+
+	log.Printf("[pollInbox] 项目 %s 自动导入: 成功 %d, 跳过 %d, 失败 %d", projectID, summary.Accepted, summary.Skipped, summary.Failed)
+	a.emit("inbox:imported", summary)
+
+	if cfg.AutoAnalyze {
+		a.autoAnalyzeImported(ctx, projectID, summary)
+	}
+}
+
+// autoAnalyzeImported 使用当前 AI 配置在后台并发分析新导入的待分析简历；项目已有分析任务时跳过
+func (a *App) autoAnalyzeImported(ctx context.Context, projectID string, summary *ImportSummary) {
+	cfg := a.currentConfig().AI
+	if checkAIConfig(&cfg) != nil {
+		log.Println("[autoAnalyzeImported] AI 未配置，跳过自动分析")
+		return
+	}
+	p := a.GetProject(projectID)
+	if p == nil {
+		return
+	}
+	var ids []string
+	var pending []*Resume
+	for _, item := range summary.Items {
+		if item.Status != ImportAccepted {
+			continue
+		}
+		r := a.loadResume(item.ResumeID)
+		if r == nil || r.Status != "pending" {
+			continue
+		}
+		ids = append(ids, item.ResumeID)
+		pending = append(pending, r)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	budget, err := a.newBudgetGuard(p, &cfg, pending)
+	if err != nil {
+		log.Printf("[autoAnalyzeImported] 跳过自动分析: %v", err)
+		return
+	}
+	// 作为任务在后台运行，不阻塞轮询：可以在前端取消或暂停，收件箱停止时随之取消
+	if _, err := a.startJob(ctx, projectID, ids, &cfg, &p.JobConfig, budget, nil); err != nil {
+		// 新简历保持待分析，下次分析项目时处理
+		log.Printf("[autoAnalyzeImported] 项目 %s 跳过自动分析: %v", projectID, err)
+	}
+}
+
+// inboxStatePath 收件箱导入记录文件
+func (a *App) inboxStatePath(projectID string) string {
+	dir := filepath.Join(a.getDataDir(), "inbox")
+	os.MkdirAll(dir, 0755)
+	return filepath.Join(dir, projectID+".json")
+}
+
+// loadInboxState 读取导入记录；目录变更后重新开始记录
+func (a *App) loadInboxState(projectID, dir string) *inboxState {
+	state := &inboxState{Dir: dir, Imported: map[string]inboxStamp{}}
+	data, err := os.ReadFile(a.inboxStatePath(projectID))
+	if err != nil {
+		return state
+	}
+	var saved inboxState
+	if json.Unmarshal(data, &saved) != nil || saved.Dir != dir || saved.Imported == nil {
+		return state
+	}
+	return &saved
+}
+
+// saveInboxState 保存导入记录
+func (a *App) saveInboxState(projectID string, state *inboxState) {
+	data, _ := json.MarshalIndent(state, "", "  ")
+	os.WriteFile(a.inboxStatePath(projectID), data, 0644)
+}
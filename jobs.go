@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ============================================
+// 分析任务：批量分析以任务形式运行，可以取消、暂停与继续
+// ============================================
+
+// 任务状态
+const (
+	JobRunning   = "running"
+	JobPaused    = "paused"
+	JobCancelled = "cancelled"
+	JobCompleted = "completed"
+)
+
+// JobInfo 分析任务的状态（前端任务列表）
+type JobInfo struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+	Done      int    `json:"done"`
+	Failed    int    `json:"failed"`
+	Reason    string `json:"reason,omitempty"` // 取消原因
+	StartedAt string `json:"started_at"`
+}
+
+// analysisJob 运行中的任务；取消通过 ctx 传递到 AI 请求，暂停只阻止开始新的简历，已发出的请求会正常完成
+type analysisJob struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	info    JobInfo
+	resumed chan struct{} // 暂停时创建，继续或取消时关闭
+
+	budget *budgetGuard // 项目预算，nil 表示不限制
+}
+
+// startJob 登记任务并在后台分析 ids，进度以 batch:progress 通知前端；parent 取消时任务随之取消。
+// projectID 不为空时同一项目只允许一个任务，检查与登记在同一把锁内完成，项目已有任务时返回错误；
+// onDone 在任务结束后、发送 batch:completed / batch:cancelled 之前调用，可以为 nil
+func (a *App) startJob(parent context.Context, projectID string, ids []string, cfg *AIConfig, jobCfg *JobConfig, budget *budgetGuard, onDone func(JobInfo)) (*analysisJob, error) {
+	ctx, cancel := context.WithCancel(parent)
+	j := &analysisJob{
+		ctx:    ctx,
+		cancel: cancel,
+		info: JobInfo{
+			ID:        fmt.Sprintf("job_%d", time.Now().UnixNano()),
+			ProjectID: projectID,
+			Status:    JobRunning,
+			Total:     len(ids),
+			StartedAt: time.Now().Format(time.RFC3339),
+		},
+		budget: budget,
+	}
+	a.jobsMu.Lock()
+	if projectID != "" && a.projectJobRunning(projectID) {
+		a.jobsMu.Unlock()
+		cancel()
+		return nil, fmt.Errorf("该项目正在分析中")
+	}
+	if a.jobs == nil {
+		a.jobs = map[string]*analysisJob{}
+	}
+	a.jobs[j.info.ID] = j
+	a.jobsMu.Unlock()
+	log.Printf("[startJob] 任务 %s 开始: 项目=%s, 共 %d 份", j.info.ID, projectID, len(ids))
+
+	go func() {
+		a.runAnalysisPool(j, ids, cfg, jobCfg, func(bp batchProgress) {
+			a.emit("batch:progress", map[string]interface{}{
+				"jobId":     bp.JobID,
+				"current":   bp.Current,
+				"total":     bp.Total,
+				"failed":    bp.Failed,
+				"status":    bp.Status,
+				"resumeId":  bp.ResumeID,
+				"projectId": projectID,
+			})
+		})
+		info := a.finishJob(j)
+		if onDone != nil {
+			onDone(info)
+		}
+		a.emitJobEnd(info)
+	}()
+	return j, nil
+}
+
+// finishJob 任务结束：未被取消时标记为完成，并从任务列表移除
+func (a *App) finishJob(j *analysisJob) JobInfo {
+	j.mu.Lock()
+	if j.info.Status != JobCancelled {
+		j.info.Status = JobCompleted
+	}
+	info := j.info
+	j.mu.Unlock()
+	j.cancel()
+
+	a.jobsMu.Lock()
+	delete(a.jobs, info.ID)
+	a.jobsMu.Unlock()
+	log.Printf("[finishJob] 任务 %s %s: 成功 %d, 失败 %d, 共 %d", info.ID, info.Status, info.Done, info.Failed, info.Total)
+	return info
+}
+
+// projectJobRunning 项目是否有正在运行的任务，调用方持有 jobsMu
+func (a *App) projectJobRunning(projectID string) bool {
+	for _, j := range a.jobs {
+		if j.info.ProjectID == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// waitIfPaused 暂停时阻塞直到继续；任务被取消时返回错误
+func (j *analysisJob) waitIfPaused() error {
+	j.mu.Lock()
+	ch := j.resumed
+	j.mu.Unlock()
+	if ch != nil {
+		select {
+		case <-ch:
+		case <-j.ctx.Done():
+		}
+	}
+	return j.ctx.Err()
+}
+
+// snapshot 当前状态
+func (j *analysisJob) snapshot() JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info
+}
+
+// record 记录一份简历的结果
+func (j *analysisJob) record(ok bool) JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if ok {
+		j.info.Done++
+	} else {
+		j.info.Failed++
+	}
+	return j.info
+}
+
+// stop 取消任务
+func (j *analysisJob) stop(reason string) {
+	j.mu.Lock()
+	if j.info.Status == JobCancelled || j.info.Status == JobCompleted {
+		j.mu.Unlock()
+		return
+	}
+	j.info.Status = JobCancelled
+	j.info.Reason = reason
+	if j.resumed != nil {
+		close(j.resumed)
+		j.resumed = nil
+	}
+	j.mu.Unlock()
+	j.cancel()
+}
+
+// cancelJobs 取消满足条件的任务
+func (a *App) cancelJobs(match func(JobInfo) bool, reason string) {
+	a.jobsMu.Lock()
+	var targets []*analysisJob
+	for _, j := range a.jobs {
+		if match(j.snapshot()) {
+			targets = append(targets, j)
+		}
+	}
+	a.jobsMu.Unlock()
+	for _, j := range targets {
+		log.Printf("[cancelJobs] 取消任务 %s: %s", j.info.ID, reason)
+		j.stop(reason)
+	}
+}
+
+// lookupJob 按 ID 查找任务
+func (a *App) lookupJob(jobID string) (*analysisJob, error) {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+	j := a.jobs[jobID]
+	if j == nil {
+		return nil, fmt.Errorf("任务不存在或已结束: %s", jobID)
+	}
+	return j, nil
+}
+
+// GetAnalysisJobs 列出正在运行或暂停的分析任务
+func (a *App) GetAnalysisJobs() []JobInfo {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+	jobs := make([]JobInfo, 0, len(a.jobs))
+	for _, j := range a.jobs {
+		jobs = append(jobs, j.snapshot())
+	}
+	return jobs
+}
+
+// CancelAnalysisJob 取消分析任务：正在进行的 AI 请求会被中断，相应简历恢复为待分析
+func (a *App) CancelAnalysisJob(jobID string) error {
+	j, err := a.lookupJob(jobID)
+	if err != nil {
+		return err
+	}
+	j.stop("用户取消")
+	return nil
+}
+
+// PauseAnalysisJob 暂停分析任务：已开始的简历会分析完，之后不再开始新的简历
+func (a *App) PauseAnalysisJob(jobID string) error {
+	j, err := a.lookupJob(jobID)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	if j.info.Status != JobRunning {
+		j.mu.Unlock()
+		return fmt.Errorf("任务当前状态为 %s，无法暂停", j.info.Status)
+	}
+	j.info.Status = JobPaused
+	j.resumed = make(chan struct{})
+	info := j.info
+	j.mu.Unlock()
+
+	a.emit("batch:paused", info)
+	return nil
+}
+
+// ResumeAnalysisJob 继续已暂停的分析任务
+func (a *App) ResumeAnalysisJob(jobID string) error {
+	j, err := a.lookupJob(jobID)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	if j.info.Status != JobPaused {
+		j.mu.Unlock()
+		return fmt.Errorf("任务未暂停")
+	}
+	j.info.Status = JobRunning
+	close(j.resumed)
+	j.resumed = nil
+	info := j.info
+	j.mu.Unlock()
+
+	a.emit("batch:resumed", info)
+	return nil
+}
+
+// emitJobEnd 任务结束时通知前端：被取消时发送 batch:cancelled，否则发送 batch:completed
+func (a *App) emitJobEnd(info JobInfo) {
+	if info.Status == JobCancelled {
+		a.emit("batch:cancelled", map[string]interface{}{
+			"jobId":     info.ID,
+			"projectId": info.ProjectID,
+			"reason":    info.Reason,
+			"current":   info.Done + info.Failed,
+			"total":     info.Total,
+			"failed":    info.Failed,
+		})
+		return
+	}
+	a.emit("batch:completed", map[string]interface{}{
+		"jobId":     info.ID,
+		"projectId": info.ProjectID,
+		"total":     info.Total,
+		"failed":    info.Failed,
+	})
+}
+
+// shutdown 应用退出时取消所有任务
+func (a *App) shutdown(ctx context.Context) {
+	a.cancelJobs(func(JobInfo) bool { return true }, "应用退出")
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ============================================
+// OCR：图片简历与扫描版 PDF
+// ============================================
+
+// OCRConfig OCR 配置
+type OCRConfig struct {
+	Engine        string `json:"engine"`         // none / tesseract / vision
+	TesseractPath string `json:"tesseract_path"` // tesseract 可执行文件路径，为空时从 PATH 查找
+	Languages     string `json:"languages"`      // tesseract 语言包，如 chi_sim+eng
+	PDFRasterizer string `json:"pdf_rasterizer"` // 可选：pdftoppm 路径，用于把扫描版 PDF 渲染为图片
+	VisionModel   string `json:"vision_model"`   // 视觉模型名，为空时使用 AI 配置中的模型
+	Timeout       int    `json:"timeout"`        // 单张图片识别超时（秒）
+}
+
+// OCREngine 图片文字识别引擎
+type OCREngine interface {
+	Name() string
+	Recognize(ctx context.Context, imagePath string) (string, error)
+}
+
+// ocrImageExts 交给 OCR 处理的图片扩展名
+var ocrImageExts = map[string]string{
+	".jpg": "image/jpeg", ".jpeg": "image/jpeg", ".png": "image/png",
+	".bmp": "image/bmp", ".gif": "image/gif", ".webp": "image/webp",
+}
+
+// ocrEngine 根据配置返回 OCR 引擎，未启用时返回 nil
+func (a *App) ocrEngine() OCREngine {
+	appCfg := a.currentConfig()
+	cfg := appCfg.OCR
+	switch cfg.Engine {
+	case "tesseract":
+		bin := cfg.TesseractPath
+		if bin == "" {
+			bin = "tesseract"
+		}
+		langs := cfg.Languages
+		if langs == "" {
+			langs = "chi_sim+eng"
+		}
+		return &tesseractOCR{binary: bin, languages: langs}
+	case "vision":
+		aiCfg := appCfg.AI
+		if cfg.VisionModel != "" {
+			aiCfg.Model = cfg.VisionModel
+		}
+		return &visionOCR{app: a, cfg: aiCfg}
+	default:
+		return nil
+	}
+}
+
+// ocrTimeout 单张图片的识别超时
+func (a *App) ocrTimeout() time.Duration {
+	if t := a.currentConfig().OCR.Timeout; t > 0 {
+		return time.Duration(t) * time.Second
+	}
+	return 120 * time.Second
+}
+
+// extractFromImage 使用配置的 OCR 引擎识别图片简历
+func (a *App) extractFromImage(ctx context.Context, filePath string) (string, error) {
+	engine := a.ocrEngine()
+	if engine == nil {
+		log.Printf("[extractFromImage] 未启用 OCR，跳过: %s", filepath.Base(filePath))
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.ocrTimeout())
+	defer cancel()
+
+	text, err := engine.Recognize(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("OCR(%s) 识别失败: %v", engine.Name(), err)
+	}
+	text = cleanOCRText(text)
+	log.Printf("[extractFromImage] %s 识别成功: %s, 长度=%d 字符", engine.Name(), filepath.Base(filePath), len(text))
+	return text, nil
+}
+
+// extractPDFWithOCR 扫描版 PDF 没有文本层时，逐页取出图片交给 OCR；ctx 取消时停止识别剩余页面
+func (a *App) extractPDFWithOCR(ctx context.Context, filePath string) (string, error) {
+	engine := a.ocrEngine()
+	if engine == nil {
+		return "", nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "talentlens-ocr-*")
+	if err != nil {
+		return "", fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	images, err := a.pdfPageImages(ctx, filePath, tmpDir)
+	if err != nil {
+		return "", err
+	}
+	if len(images) == 0 {
+		return "", fmt.Errorf("扫描版 PDF 中未找到可识别的页面图片（仅支持 JPEG 图片，或配置 pdftoppm 渲染）")
+	}
+
+	var pages []string
+	for i, img := range images {
+		pageCtx, cancel := context.WithTimeout(ctx, a.ocrTimeout())
+		text, err := engine.Recognize(pageCtx, img)
+		cancel()
+		if err != nil {
+			return "", fmt.Errorf("OCR(%s) 识别第 %d 页失败: %v", engine.Name(), i+1, err)
+		}
+		if text = cleanOCRText(text); text != "" {
+			pages = append(pages, text)
+		}
+	}
+
+	result := strings.Join(pages, "\n")
+	log.Printf("[extractPDFWithOCR] %s 识别成功: %s, %d 页, 长度=%d 字符", engine.Name(), filepath.Base(filePath), len(images), len(result))
+	return result, nil
+}
+
+// pdfPageImages 把 PDF 页面转成图片文件：优先使用配置的 pdftoppm，否则取出内嵌的 JPEG 扫描图
+func (a *App) pdfPageImages(ctx context.Context, filePath, outDir string) ([]string, error) {
+	if bin := a.currentConfig().OCR.PDFRasterizer; bin != "" {
+		ctx, cancel := context.WithTimeout(ctx, a.ocrTimeout())
+		defer cancel()
+		prefix := filepath.Join(outDir, "page")
+		cmd := exec.CommandContext(ctx, bin, "-r", "200", "-png", filePath, prefix)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("pdftoppm 渲染失败: %v %s", err, strings.TrimSpace(string(out)))
+		}
+		images, _ := filepath.Glob(prefix + "*.png")
+		sort.Strings(images)
+		return images, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取 PDF 失败: %v", err)
+	}
+	var images []string
+	for i, jpg := range pdfEmbeddedJPEGs(data) {
+		p := filepath.Join(outDir, fmt.Sprintf("page-%03d.jpg", i+1))
+		if err := os.WriteFile(p, jpg, 0644); err != nil {
+			return nil, fmt.Errorf("写入临时图片失败: %v", err)
+		}
+		images = append(images, p)
+	}
+	return images, nil
+}
+
+// pdfEmbeddedJPEGs 在 PDF 原始字节中查找 DCTDecode 图片流，流数据本身就是完整的 JPEG 文件
+func pdfEmbeddedJPEGs(data []byte) [][]byte {
+	var images [][]byte
+	marker := []byte("/DCTDecode")
+	pos := 0
+	for {
+		idx := bytes.Index(data[pos:], marker)
+		if idx == -1 {
+			break
+		}
+		pos += idx + len(marker)
+
+		// 同一对象内的 stream 关键字之后即为图片数据
+		end := bytes.Index(data[pos:], []byte("endobj"))
+		if end == -1 {
+			break
+		}
+		obj := data[pos : pos+end]
+		s := bytes.Index(obj, []byte("stream"))
+		if s == -1 {
+			continue
+		}
+		body := obj[s+len("stream"):]
+		body = bytes.TrimLeft(body, "\r\n")
+		if e := bytes.LastIndex(body, []byte("endstream")); e != -1 {
+			body = body[:e]
+		}
+		// 截到 JPEG 结束标记，去掉流末尾的换行
+		if e := bytes.LastIndex(body, []byte{0xFF, 0xD9}); e != -1 {
+			body = body[:e+2]
+		}
+		if len(body) > 4 && body[0] == 0xFF && body[1] == 0xD8 {
+			images = append(images, body)
+		}
+		pos += end
+	}
+	return images
+}
+
+// cleanOCRText 清理 OCR 输出的空白行
+func cleanOCRText(text string) string {
+	var cleaned []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, "\n")
+}
+
+// tesseractOCR 调用本地 tesseract 命令识别
+type tesseractOCR struct {
+	binary    string
+	languages string
+}
+
+func (t *tesseractOCR) Name() string { return "tesseract" }
+
+func (t *tesseractOCR) Recognize(ctx context.Context, imagePath string) (string, error) {
+	cmd := exec.CommandContext(ctx, t.binary, imagePath, "stdout", "-l", t.languages)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("识别超时")
+		}
+		return "", fmt.Errorf("%v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// visionOCR 把图片发送给支持视觉输入的模型转写文字
+type visionOCR struct {
+	app *App
+	cfg AIConfig
+}
+
+func (v *visionOCR) Name() string { return "vision" }
+
+func (v *visionOCR) Recognize(ctx context.Context, imagePath string) (string, error) {
+	if err := checkAIConfig(&v.cfg); err != nil {
+		return "", fmt.Errorf("AI 未配置，无法使用视觉模型识别")
+	}
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", err
+	}
+	mime, ok := ocrImageExts[strings.ToLower(filepath.Ext(imagePath))]
+	if !ok {
+		mime = "image/jpeg"
+	}
+
+	reqBody := &ChatRequest{
+		Model: v.cfg.Model,
+		Messages: []ChatMessage{
+			{
+				Role:    "user",
+				Content: "请逐字转写这张简历图片中的全部文字，按从上到下、从左到右的阅读顺序输出纯文本，保留分段，不要添加任何解释。",
+				Images:  []ChatImage{{MIME: mime, Data: data}},
+			},
+		},
+		MaxTokens: 4000,
+	}
+	res, err := v.app.chatWithRetry(ctx, &v.cfg, reqBody)
+	if err != nil {
+		return "", err
+	}
+	return res.Content, nil
+}
+
+// CheckOCREngine 检查 OCR 引擎是否可用（设置页测试按钮）
+func (a *App) CheckOCREngine(cfg *OCRConfig) (bool, string) {
+	switch cfg.Engine {
+	case "", "none":
+		return false, "未启用 OCR"
+	case "tesseract":
+		bin := cfg.TesseractPath
+		if bin == "" {
+			bin = "tesseract"
+		}
+		out, err := exec.Command(bin, "--version").CombinedOutput()
+		if err != nil {
+			return false, fmt.Sprintf("无法运行 tesseract: %v", err)
+		}
+		version := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+		return true, "tesseract 可用: " + version
+	case "vision":
+		aiCfg := a.currentConfig().AI
+		if cfg.VisionModel != "" {
+			aiCfg.Model = cfg.VisionModel
+		}
+		return a.TestAIConnection(&aiCfg)
+	default:
+		return false, "未知的 OCR 引擎: " + cfg.Engine
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ============================================
+// 版面感知的 PDF 文本提取（按行、分栏、阅读顺序）
+// ============================================
+
+// ExtractConfig 文本提取配置
+type ExtractConfig struct {
+	PDFStrategy string `json:"pdf_strategy"` // layout（默认，按版面还原阅读顺序）/ plain（库自带的纯文本）
+}
+
+// pdfGlyph 页面上的一个字形（或连续字符串）及其位置
+type pdfGlyph struct {
+	x, y, w  float64
+	fontSize float64
+	bold     bool
+	space    bool // 空白字形：只用于判断词间空格，不参与分栏判断
+	s        string
+}
+
+// pdfRow 基线相同（y 接近）的一行字形
+type pdfRow struct {
+	y      float64
+	glyphs []pdfGlyph
+}
+
+// pdfLine 输出前的一行文本及排版信息
+type pdfLine struct {
+	text     string
+	y        float64
+	fontSize float64
+	bold     bool
+}
+
+// pdfBulletRunes 常见项目符号（含 Symbol/Wingdings 私用区字符），出现在行首时统一为 •
+var pdfBulletRunes = map[rune]bool{
+	'•': true, '●': true, '▪': true, '■': true, '◆': true, '◦': true, '○': true, '►': true, '➢': true, '✓': true,
+	0xF0A7: true, 0xF0B7: true, 0xF06C: true, 0xF0D8: true, 0xF0FC: true,
+}
+
+// pdfLayoutText 逐页按版面提取文本：检测双栏，分栏输出，保留标题与段落间距
+func pdfLayoutText(r *pdf.Reader) (result string, err error) {
+	// 内容流中出现库不支持的操作符时会 panic，交由调用方回退到纯文本方式
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("版面解析失败: %v", p)
+		}
+	}()
+
+	var pages []string
+	for i := 1; i <= r.NumPage(); i++ {
+		page := r.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		if text := pdfPageLayout(page); text != "" {
+			pages = append(pages, text)
+		}
+	}
+	return strings.Join(pages, "\n\n"), nil
+}
+
+// pdfPageLayout 处理单页
+func pdfPageLayout(page pdf.Page) string {
+	var glyphs []pdfGlyph
+	zeroWidth := 0
+	for _, t := range page.Content().Text {
+		if t.S == "\n" || t.S == "" {
+			continue
+		}
+		g := pdfGlyph{x: t.X, y: t.Y, w: t.W, fontSize: t.FontSize, s: t.S, space: strings.TrimSpace(t.S) == ""}
+		if g.fontSize <= 0 {
+			g.fontSize = 10
+		}
+		if g.w <= 0 {
+			zeroWidth++
+			g.w = estimateGlyphWidth(t.S, g.fontSize)
+		}
+		lower := strings.ToLower(t.Font)
+		g.bold = strings.Contains(lower, "bold") || strings.Contains(lower, "heavy") || strings.Contains(lower, "black")
+		glyphs = append(glyphs, g)
+	}
+	if len(glyphs) == 0 {
+		return ""
+	}
+	// 字体缺少宽度信息时库不会推进字形位置，无法判断版面，交给纯文本方式处理
+	if zeroWidth*2 > len(glyphs) {
+		return ""
+	}
+
+	rows := groupPDFRows(glyphs)
+	bodySize := medianFontSize(glyphs)
+
+	pageWidth := 0.0
+	if box := pdfMediaBox(page); box.Len() == 4 {
+		pageWidth = box.Index(2).Float64() - box.Index(0).Float64()
+	}
+	if pageWidth <= 0 {
+		for _, g := range glyphs {
+			pageWidth = math.Max(pageWidth, g.x+g.w)
+		}
+	}
+
+	gutter, ok := findPDFGutter(rows, pageWidth)
+	var lines []pdfLine
+	if !ok {
+		for _, row := range rows {
+			lines = append(lines, buildPDFLine(row.glyphs))
+		}
+		return renderPDFLines([][]pdfLine{lines}, bodySize)
+	}
+
+	// 跨越分栏线的行（如顶部的姓名、联系方式）把页面切成若干区块；
+	// 每个区块内先输出左栏，再输出右栏
+	var blocks [][]pdfLine
+	var left, right []pdfLine
+	flush := func() {
+		if len(left) > 0 {
+			blocks = append(blocks, left)
+		}
+		if len(right) > 0 {
+			blocks = append(blocks, right)
+		}
+		left, right = nil, nil
+	}
+	for _, row := range rows {
+		if rowCrosses(row, gutter) {
+			flush()
+			blocks = append(blocks, []pdfLine{buildPDFLine(row.glyphs)})
+			continue
+		}
+		var l, r []pdfGlyph
+		for _, g := range row.glyphs {
+			if g.x+g.w/2 < gutter {
+				l = append(l, g)
+			} else {
+				r = append(r, g)
+			}
+		}
+		if len(l) > 0 {
+			left = append(left, buildPDFLine(l))
+		}
+		if len(r) > 0 {
+			right = append(right, buildPDFLine(r))
+		}
+	}
+	flush()
+	return renderPDFLines(blocks, bodySize)
+}
+
+// pdfMediaBox 查找页面尺寸（MediaBox 可继承自父节点）
+func pdfMediaBox(page pdf.Page) pdf.Value {
+	for v := page.V; !v.IsNull(); v = v.Key("Parent") {
+		if box := v.Key("MediaBox"); !box.IsNull() {
+			return box
+		}
+	}
+	return pdf.Value{}
+}
+
+// estimateGlyphWidth 字体未提供宽度时估算：全角字符约 1em，其余约 0.5em
+func estimateGlyphWidth(s string, fontSize float64) float64 {
+	w := 0.0
+	for _, r := range s {
+		if isWideRune(r) {
+			w += fontSize
+		} else {
+			w += fontSize * 0.5
+		}
+	}
+	return w
+}
+
+// isWideRune 判断中日韩等全角字符
+func isWideRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) || (r >= 0xFF00 && r <= 0xFFEF) || (r >= 0x3000 && r <= 0x303F)
+}
+
+// groupPDFRows 按基线把字形归并为行，行内按 x 排序，行间自上而下
+func groupPDFRows(glyphs []pdfGlyph) []pdfRow {
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		if glyphs[i].y != glyphs[j].y {
+			return glyphs[i].y > glyphs[j].y
+		}
+		return glyphs[i].x < glyphs[j].x
+	})
+
+	var rows []pdfRow
+	for _, g := range glyphs {
+		tol := math.Max(1.5, g.fontSize*0.3)
+		if n := len(rows); n > 0 && math.Abs(rows[n-1].y-g.y) <= tol {
+			rows[n-1].glyphs = append(rows[n-1].glyphs, g)
+			continue
+		}
+		rows = append(rows, pdfRow{y: g.y, glyphs: []pdfGlyph{g}})
+	}
+	for i := range rows {
+		gs := rows[i].glyphs
+		sort.SliceStable(gs, func(a, b int) bool { return gs[a].x < gs[b].x })
+	}
+	return rows
+}
+
+// medianFontSize 正文字号（按字形中位数）
+func medianFontSize(glyphs []pdfGlyph) float64 {
+	sizes := make([]float64, len(glyphs))
+	for i, g := range glyphs {
+		sizes[i] = g.fontSize
+	}
+	sort.Float64s(sizes)
+	return sizes[len(sizes)/2]
+}
+
+// rowCrosses 判断该行是否有字形跨越分栏线
+func rowCrosses(row pdfRow, x float64) bool {
+	for _, g := range row.glyphs {
+		if !g.space && g.x < x-1 && g.x+g.w > x+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// findPDFGutter 在页面中部寻找一条大部分行都不跨越、且两侧都有独立内容的竖直空白带
+func findPDFGutter(rows []pdfRow, pageWidth float64) (float64, bool) {
+	if len(rows) < 8 || pageWidth <= 0 {
+		return 0, false
+	}
+
+	bestX, bestScore := 0.0, 0
+	for x := pageWidth * 0.2; x <= pageWidth*0.8; x += 2 {
+		crossing, both, leftOnly, rightOnly := 0, 0, 0, 0
+		for _, row := range rows {
+			if rowCrosses(row, x) {
+				crossing++
+				continue
+			}
+			hasLeft, hasRight := false, false
+			gap := math.MaxFloat64
+			for _, g := range row.glyphs {
+				if g.space {
+					continue
+				}
+				if g.x+g.w/2 < x {
+					hasLeft = true
+					gap = math.Min(gap, x-(g.x+g.w))
+				} else {
+					hasRight = true
+					gap = math.Min(gap, g.x-x)
+				}
+			}
+			switch {
+			case hasLeft && hasRight:
+				// 两侧文字距离分栏线太近，更像是普通的词间空格
+				if gap*2 < row.glyphs[0].fontSize {
+					crossing++
+					continue
+				}
+				both++
+			case hasLeft:
+				leftOnly++
+			case hasRight:
+				rightOnly++
+			}
+		}
+
+		// 跨栏行不超过 15%；两栏各自至少有 3 行；且两栏行基线并非一一对齐（排除"标签: 值"式表格）
+		if crossing*100 > len(rows)*15 {
+			continue
+		}
+		if leftOnly+both < 3 || rightOnly+both < 3 {
+			continue
+		}
+		if (leftOnly+rightOnly)*100 < len(rows)*30 {
+			continue
+		}
+		score := both + leftOnly + rightOnly - crossing*3
+		if score > bestScore {
+			bestX, bestScore = x, score
+		}
+	}
+	return bestX, bestScore > 0
+}
+
+// buildPDFLine 把同一行的字形拼成文本，根据间距插入空格
+func buildPDFLine(glyphs []pdfGlyph) pdfLine {
+	var sb strings.Builder
+	var line pdfLine
+	boldChars, total, count := 0, 0, 0
+	pendingSpace := false
+	var prev *pdfGlyph
+	for i := range glyphs {
+		g := &glyphs[i]
+		if g.space {
+			pendingSpace = prev != nil
+			continue
+		}
+		if prev != nil {
+			gap := g.x - (prev.x + prev.w)
+			last, _ := utf8.DecodeLastRuneInString(prev.s)
+			first, _ := utf8.DecodeRuneInString(g.s)
+			threshold := g.fontSize * 0.2
+			if isWideRune(last) || isWideRune(first) {
+				threshold = g.fontSize * 0.5
+			}
+			if pendingSpace || gap > threshold {
+				sb.WriteByte(' ')
+			}
+		}
+		pendingSpace = false
+		sb.WriteString(g.s)
+		line.y += g.y
+		count++
+		line.fontSize = math.Max(line.fontSize, g.fontSize)
+		n := utf8.RuneCountInString(g.s)
+		total += n
+		if g.bold {
+			boldChars += n
+		}
+		prev = g
+	}
+	if count > 0 {
+		line.y /= float64(count)
+	}
+	line.text = normalizePDFBullet(strings.TrimSpace(sb.String()))
+	line.bold = total > 0 && boldChars*2 > total
+	return line
+}
+
+// normalizePDFBullet 统一行首项目符号
+func normalizePDFBullet(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if pdfBulletRunes[r] {
+		return "• " + strings.TrimSpace(text[size:])
+	}
+	return text
+}
+
+// renderPDFLines 输出各区块；标题（大字号或加粗短行）和段间距较大处插入空行，保留分节边界
+func renderPDFLines(blocks [][]pdfLine, bodySize float64) string {
+	var out []string
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+	for bi, block := range blocks {
+		if bi > 0 {
+			blank()
+		}
+		for i, line := range block {
+			if line.text == "" {
+				continue
+			}
+			heading := line.fontSize >= bodySize*1.2 ||
+				(line.bold && utf8.RuneCountInString(line.text) <= 30 && line.fontSize >= bodySize)
+			if i > 0 {
+				gap := block[i-1].y - line.y
+				if heading || gap > math.Max(line.fontSize, block[i-1].fontSize)*1.9 {
+					blank()
+				}
+			}
+			out = append(out, line.text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
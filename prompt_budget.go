@@ -0,0 +1,212 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ============================================
+// 按模型上下文窗口估算 token，构建不超预算的分析提示词
+// ============================================
+
+const (
+	analysisMaxTokens      = 4000 // 分析请求为回复预留的 max_tokens
+	promptSafetyMargin     = 500  // 估算误差余量
+	maxResumePromptTokens  = 8000 // 简历部分上限，超大窗口模型也不必塞入整份原文
+	minResumePromptTokens  = 500  // 窗口过小时至少保留的简历篇幅
+	defaultContextWindow   = 32000
+	defaultHanTokenWeight  = 1.0
+	latinRuneTokenWeight   = 0.25 // 英文约 4 个字符一个 token
+	sectionTruncateMinimum = 100  // 剩余预算低于该值时不再截取半节
+)
+
+// modelProfile 模型的上下文窗口与中文 token 折算系数（每个汉字约几个 token）
+type modelProfile struct {
+	prefix        string
+	contextWindow int
+	hanWeight     float64
+}
+
+// modelProfiles 常见模型，按模型名前缀匹配（取最长前缀）
+var modelProfiles = []modelProfile{
+	{"gpt-4o", 128000, 0.8},
+	{"gpt-4.1", 1000000, 0.8},
+	{"gpt-4-turbo", 128000, 1.2},
+	{"gpt-4-32k", 32768, 1.2},
+	{"gpt-4", 8192, 1.2},
+	{"gpt-3.5-turbo", 16385, 1.2},
+	{"o1", 128000, 0.8},
+	{"o3", 200000, 0.8},
+	{"o4", 200000, 0.8},
+	{"claude", 200000, 1.0},
+	{"gemini", 1000000, 0.8},
+	{"deepseek", 64000, 0.6},
+	{"qwen-long", 1000000, 0.7},
+	{"qwen", 32000, 0.7},
+	{"glm-4", 128000, 0.7},
+	{"moonshot-v1-8k", 8192, 0.7},
+	{"moonshot-v1-32k", 32768, 0.7},
+	{"moonshot-v1-128k", 128000, 0.7},
+	{"llama", 8192, 1.3},
+	{"mistral", 32000, 1.2},
+}
+
+// PromptBudget 记录提示词的 token 预算与被省略的内容
+type PromptBudget struct {
+	Model           string   `json:"model"`
+	ContextWindow   int      `json:"context_window"`
+	EstimatedTokens int      `json:"estimated_tokens"`           // 整个提示词的估算 token 数
+	ResumeBudget    int      `json:"resume_budget"`              // 分配给简历内容的 token 数
+	Truncated       bool     `json:"truncated"`                  // 简历内容是否被截断或省略了部分分节
+	DroppedSections []string `json:"dropped_sections,omitempty"` // 因篇幅省略的分节标题
+}
+
+// tokenEstimator 粗略估算 token 数：全角字符按模型系数计，其余字符按 4 个一 token 计
+type tokenEstimator struct {
+	hanWeight float64
+}
+
+// lookupModelProfile 查找模型配置，未知模型使用保守的默认值
+func lookupModelProfile(model string) modelProfile {
+	name := strings.ToLower(strings.TrimSpace(model))
+	// 兼容 "openai/gpt-4o"、"deepseek-ai/DeepSeek-V3" 这类带组织前缀的名称
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	best := modelProfile{contextWindow: defaultContextWindow, hanWeight: defaultHanTokenWeight}
+	for _, p := range modelProfiles {
+		if strings.HasPrefix(name, p.prefix) && len(p.prefix) > len(best.prefix) {
+			best = p
+		}
+	}
+	return best
+}
+
+// runeTokens 单个字符的 token 权重
+func (e tokenEstimator) runeTokens(r rune) float64 {
+	if isWideRune(r) {
+		return e.hanWeight
+	}
+	return latinRuneTokenWeight
+}
+
+// Count 估算文本的 token 数
+func (e tokenEstimator) Count(s string) int {
+	total := 0.0
+	for _, r := range s {
+		total += e.runeTokens(r)
+	}
+	return int(math.Ceil(total))
+}
+
+// Truncate 截取不超过 maxTokens 的前缀，不会切断多字节字符
+func (e tokenEstimator) Truncate(s string, maxTokens int) string {
+	total := 0.0
+	for i, r := range s {
+		total += e.runeTokens(r)
+		if total > float64(maxTokens) {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// buildAnalysisPrompt 构建分析提示词：岗位信息完整保留，简历内容按模型上下文窗口裁剪
+func (a *App) buildAnalysisPrompt(resume *Resume, jobCfg *JobConfig, model string) (string, *PromptBudget) {
+	profile := lookupModelProfile(model)
+	est := tokenEstimator{hanWeight: profile.hanWeight}
+
+	budget := &PromptBudget{Model: model, ContextWindow: profile.contextWindow}
+	fixed := est.Count(a.renderAnalysisPrompt(resume.FileName, jobCfg, ""))
+	available := profile.contextWindow - analysisMaxTokens - promptSafetyMargin - fixed
+	budget.ResumeBudget = min(available, maxResumePromptTokens)
+	if budget.ResumeBudget < minResumePromptTokens {
+		log.Printf("[buildAnalysisPrompt] 模型 %s 上下文窗口不足（窗口=%d, 固定部分=%d），简历内容仅保留 %d tokens",
+			model, profile.contextWindow, fixed, minResumePromptTokens)
+		budget.ResumeBudget = minResumePromptTokens
+	}
+
+	content, dropped, truncated := fitResumeContent(resume, jobCfg.RequiredSkills, budget.ResumeBudget, est)
+	budget.DroppedSections = dropped
+	budget.Truncated = truncated
+
+	prompt := a.renderAnalysisPrompt(resume.FileName, jobCfg, content)
+	budget.EstimatedTokens = est.Count(prompt)
+	if truncated {
+		log.Printf("[buildAnalysisPrompt] %s 简历内容超出预算（%d tokens），已裁剪，省略分节: %v",
+			resume.FileName, budget.ResumeBudget, dropped)
+	}
+	return prompt, budget
+}
+
+// fitResumeContent 简历超出预算时按分节优先级与岗位相关度挑选内容，而不是直接截掉尾部
+func fitResumeContent(resume *Resume, keywords []string, maxTokens int, est tokenEstimator) (string, []string, bool) {
+	if est.Count(resume.Content) <= maxTokens {
+		return resume.Content, nil, false
+	}
+	sections := resume.Sections
+	if len(sections) == 0 {
+		sections = segmentResume(resume.Content)
+	}
+	if len(sections) <= 1 {
+		return est.Truncate(resume.Content, maxTokens) + "\n...(内容已截断)", nil, true
+	}
+	text, dropped := prioritizeSections(sections, keywords, maxTokens, est)
+	if len(dropped) > 0 {
+		text += "\n...(以下分节因篇幅省略: " + strings.Join(dropped, "、") + ")"
+	}
+	return text, dropped, true
+}
+
+// sectionOrder 分节的选取顺序：先按分节类型优先级，再按与岗位技能的相关度；
+// 命中多个必备技能的次要分节（如"其他"中的开源经历）提前到项目经历之后
+func sectionOrder(sections []ResumeSection, keywords []string) []int {
+	rank := make(map[string]int, len(sectionPriority))
+	for i, kind := range sectionPriority {
+		rank[kind] = i
+	}
+	promoted := rank[SectionProjects]
+
+	type entry struct {
+		index, rank, hits int
+	}
+	entries := make([]entry, len(sections))
+	for i, sec := range sections {
+		r, ok := rank[sec.Kind]
+		if !ok {
+			r = len(sectionPriority)
+		}
+		hits := sectionRelevance(sec, keywords)
+		if hits >= 2 && r > promoted {
+			r = promoted
+		}
+		entries[i] = entry{index: i, rank: r, hits: hits}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].rank != entries[j].rank {
+			return entries[i].rank < entries[j].rank
+		}
+		return entries[i].hits > entries[j].hits
+	})
+
+	order := make([]int, len(entries))
+	for i, e := range entries {
+		order[i] = e.index
+	}
+	return order
+}
+
+// sectionRelevance 分节中出现的岗位技能关键词个数（忽略大小写）
+func sectionRelevance(sec ResumeSection, keywords []string) int {
+	text := strings.ToLower(sec.Heading + "\n" + sec.Content)
+	hits := 0
+	for _, kw := range keywords {
+		kw = strings.ToLower(strings.TrimSpace(kw))
+		if kw != "" && strings.Contains(text, kw) {
+			hits++
+		}
+	}
+	return hits
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ============================================
+// AI 服务商：按 AIConfig.Provider 分派到不同的接口适配器
+// ============================================
+
+// 服务商类型；其余取值（openai / deepseek / zhipu / moonshot / siliconflow / custom 等）均按 OpenAI 兼容接口处理
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderGemini    = "gemini"
+	ProviderAzure     = "azure"
+	ProviderOllama    = "ollama"
+)
+
+// defaultAITimeout AIConfig.Timeout 未设置时的请求超时（秒）
+const defaultAITimeout = 60
+
+// Provider AI 服务商接口
+type Provider interface {
+	Name() string
+	Chat(ctx context.Context, req *ChatRequest) (*ChatResult, error)
+	ListModels(ctx context.Context) ([]string, error)
+	Test(ctx context.Context) error
+}
+
+// ChatResult 一次对话的结果
+type ChatResult struct {
+	Content string
+	Model   string  // 服务端返回的模型名，可能为空
+	Usage   AIUsage // 服务商报告的 token 用量，未报告时由 chatWithRetry 估算
+}
+
+// ChatImage 随消息发送的图片（视觉 OCR）
+type ChatImage struct {
+	MIME string
+	Data []byte
+}
+
+// ResponseFormat 要求模型按 JSON Schema 输出：OpenAI / Azure 使用 json_schema（其余 OpenAI 兼容接口使用 json_object），
+// Anthropic 使用强制调用的工具，Gemini 使用 responseSchema，Ollama 使用 format
+type ResponseFormat struct {
+	Name   string
+	Schema map[string]interface{}
+}
+
+// apiError 服务商返回的非 2xx 响应
+type apiError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // 响应头要求的等待时间，见 retryAfterHeader
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API错误: %d - %s", e.StatusCode, e.Message)
+}
+
+// providerKind 归一化的服务商类型
+func providerKind(cfg *AIConfig) string {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "anthropic", "claude":
+		return ProviderAnthropic
+	case "gemini", "google":
+		return ProviderGemini
+	case "azure", "azure-openai":
+		return ProviderAzure
+	case "ollama":
+		return ProviderOllama
+	default:
+		return "openai"
+	}
+}
+
+// newProvider 根据配置创建服务商适配器
+func newProvider(cfg *AIConfig) Provider {
+	client := &http.Client{Timeout: aiTimeout(cfg)}
+	switch providerKind(cfg) {
+	case ProviderAnthropic:
+		return &anthropicProvider{cfg: *cfg, client: client}
+	case ProviderGemini:
+		return &geminiProvider{cfg: *cfg, client: client}
+	case ProviderAzure:
+		return &openAIProvider{cfg: *cfg, client: client, azure: true}
+	case ProviderOllama:
+		return &ollamaProvider{cfg: *cfg, client: client}
+	default:
+		return &openAIProvider{cfg: *cfg, client: client}
+	}
+}
+
+// aiTimeout 单次请求超时
+func aiTimeout(cfg *AIConfig) time.Duration {
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+	return defaultAITimeout * time.Second
+}
+
+// checkAIConfig 校验调用 AI 所需的配置；Ollama 不需要 API Key，Anthropic / Gemini / Ollama 有默认地址
+func checkAIConfig(cfg *AIConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("AI 未配置: 请先在设置中填写 API Key")
+	}
+	kind := providerKind(cfg)
+	if cfg.APIKey == "" && kind != ProviderOllama {
+		return fmt.Errorf("AI 未配置: 请先在设置中填写 API Key")
+	}
+	if cfg.BaseURL == "" && (kind == "openai" || kind == ProviderAzure) {
+		return fmt.Errorf("AI 未配置: 请先在设置中填写 Base URL")
+	}
+	return nil
+}
+
+// chatWithRetry 发送对话请求（失败时重试），返回回复与用量；每次请求前按服务商限流。
+// req.OnStream 非空且服务商支持时使用流式输出，只要持续有数据就不受单次请求超时限制
+func (a *App) chatWithRetry(ctx context.Context, cfg *AIConfig, req *ChatRequest) (*ChatResult, error) {
+	p := newProvider(cfg)
+	retries := max(cfg.MaxRetries, 1)
+	send := func(req *ChatRequest) (*ChatResult, error) {
+		if err := a.waitRateLimit(ctx, cfg, estimateRequestTokens(cfg, req)); err != nil {
+			return nil, err
+		}
+		if sp, ok := p.(StreamingProvider); ok && req.OnStream != nil && !cfg.DisableStream {
+			return streamChat(ctx, sp, cfg, req)
+		}
+		return p.Chat(ctx, req)
+	}
+
+	var lastErr *AIError
+	for attempt := 0; attempt < retries; attempt++ {
+		if attempt > 0 {
+			if err := sleepCtx(ctx, retryDelay(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+		res, err := send(req)
+		if err != nil && req.ResponseFormat != nil && classifyAIError(err).Kind == AIErrBadRequest {
+			// 部分 OpenAI 兼容服务不支持 response_format，去掉后立即重试，由本地校验保证格式
+			log.Printf("[chatWithRetry] %s 不支持结构化输出，改用普通请求: %v", p.Name(), err)
+			plain := *req
+			plain.ResponseFormat = nil
+			req = &plain
+			res, err = send(req)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			if res.Usage.PromptTokens == 0 && res.Usage.CompletionTokens == 0 {
+				res.Usage = estimateUsage(cfg, req, res.Content)
+			}
+			return res, nil
+		}
+		lastErr = classifyAIError(err)
+		log.Printf("[chatWithRetry] %s 第 %d 次请求失败（%s）: %v", p.Name(), attempt+1, lastErr.Kind, err)
+		if !lastErr.Retryable() {
+			return nil, lastErr
+		}
+	}
+	return nil, fmt.Errorf("重试%d次后失败: %w", retries, lastErr)
+}
+
+// testChat 发送一条很短的对话验证 Key、地址与模型是否可用
+func testChat(ctx context.Context, p Provider) error {
+	_, err := p.Chat(ctx, &ChatRequest{
+		Messages: []ChatMessage{
+			{Role: "user", Content: "Hello, this is a connection test. Please respond with 'OK'."},
+		},
+		MaxTokens: 10,
+	})
+	return err
+}
+
+// ListAIModels 列出服务商可用的模型（设置页模型下拉框）
+func (a *App) ListAIModels(cfg *AIConfig) ([]string, error) {
+	if err := checkAIConfig(cfg); err != nil {
+		return nil, err
+	}
+	return newProvider(cfg).ListModels(context.Background())
+}
+
+// describeAIError 把连接测试的错误转换为用户可读的提示
+func describeAIError(err error) string {
+	ae := classifyAIError(err)
+	switch ae.Kind {
+	case AIErrUnknown:
+		return fmt.Sprintf("连接失败: %v", err)
+	case AIErrNetwork:
+		return fmt.Sprintf("%s（%v）", ae.Hint(), err)
+	case AIErrBadRequest:
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+			return "接口地址或模型不存在: " + apiErr.Message
+		}
+		return fmt.Sprintf("%s（%v）", ae.Hint(), err)
+	default:
+		return ae.Hint()
+	}
+}
+
+// joinAPIURL 拼接接口地址；baseURL 已包含版本前缀（如 .../v1）时不再重复
+func joinAPIURL(baseURL, version, path string) string {
+	base := strings.TrimSuffix(baseURL, "/")
+	if version != "" && !strings.HasSuffix(base, "/"+version) {
+		base += "/" + version
+	}
+	return base + path
+}
+
+// doJSON 发送 JSON 请求，2xx 时把响应解析到 out，否则返回 *apiError
+func doJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &apiError{StatusCode: resp.StatusCode, Message: apiErrorMessage(respBody), RetryAfter: retryAfterHeader(resp.Header)}
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+	return nil
+}
+
+// apiErrorMessage 从错误响应中取出说明；各家格式不同：
+// {"error":{"message":...}}（OpenAI / Anthropic / Gemini）、{"error":"..."}（Ollama）
+func apiErrorMessage(body []byte) string {
+	var wrapped struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &wrapped) == nil {
+		var obj struct {
+			Message string `json:"message"`
+		}
+		var s string
+		switch {
+		case json.Unmarshal(wrapped.Error, &obj) == nil && obj.Message != "":
+			return obj.Message
+		case json.Unmarshal(wrapped.Error, &s) == nil && s != "":
+			return s
+		case wrapped.Message != "":
+			return wrapped.Message
+		}
+	}
+	return truncateRunes(strings.TrimSpace(string(body)), 500)
+}
+
+// splitSystem 把 system 消息合并为一段（Anthropic / Gemini 单独传递 system 提示词）
+func splitSystem(messages []ChatMessage) (string, []ChatMessage) {
+	var system []string
+	var rest []ChatMessage
+	for _, m := range messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		rest = append(rest, m)
+	}
+	return strings.Join(system, "\n\n"), rest
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ============================================
+// Anthropic Messages API
+// ============================================
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	anthropicAPIVersion     = "2023-06-01"
+	anthropicMaxTokens      = 4096 // Messages API 要求必须指定 max_tokens
+)
+
+// anthropicProvider Claude 系列模型
+type anthropicProvider struct {
+	cfg    AIConfig
+	client *http.Client
+}
+
+type anthropicContent struct {
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *anthropicImageSource `json:"source,omitempty"`
+	Input  json.RawMessage       `json:"input,omitempty"` // tool_use 的参数
+}
+
+// anthropicTool 结构化输出通过强制调用工具实现，工具参数即为要求的 JSON
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"` // tool
+	Name string `json:"name"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"` // base64
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+type anthropicMessage struct {
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	System      string               `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature float64              `json:"temperature,omitempty"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Stream      bool                 `json:"stream,omitempty"`
+}
+
+// anthropicStreamEvent 流式响应事件（message_start / content_block_delta / message_delta / error 等）
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string         `json:"model"`
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta *struct {
+		Type        string `json:"type"` // text_delta / input_json_delta
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"` // overloaded_error / api_error / rate_limit_error ...
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type anthropicResponse struct {
+	Model      string             `json:"model"`
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      anthropicUsage     `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (p *anthropicProvider) Name() string { return ProviderAnthropic }
+
+// endpoint 接口地址，Base URL 可以带或不带 /v1
+func (p *anthropicProvider) endpoint(path string) string {
+	base := p.cfg.BaseURL
+	if base == "" {
+		base = defaultAnthropicBaseURL
+	}
+	return joinAPIURL(base, "v1", path)
+}
+
+func (p *anthropicProvider) headers() map[string]string {
+	return map[string]string{
+		"x-api-key":         p.cfg.APIKey,
+		"anthropic-version": anthropicAPIVersion,
+	}
+}
+
+// buildRequest 转换为 Messages API 请求体
+func (p *anthropicProvider) buildRequest(req *ChatRequest) anthropicRequest {
+	system, messages := splitSystem(req.Messages)
+	body := anthropicRequest{
+		Model:       p.cfg.Model,
+		System:      system,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = anthropicMaxTokens
+	}
+	if f := req.ResponseFormat; f != nil {
+		body.Tools = []anthropicTool{{Name: f.Name, Description: "按要求的格式提交结果", InputSchema: f.Schema}}
+		body.ToolChoice = &anthropicToolChoice{Type: "tool", Name: f.Name}
+	}
+	for _, m := range messages {
+		var content []anthropicContent
+		for _, img := range m.Images {
+			content = append(content, anthropicContent{Type: "image", Source: &anthropicImageSource{
+				Type: "base64", MediaType: img.MIME, Data: base64.StdEncoding.EncodeToString(img.Data),
+			}})
+		}
+		content = append(content, anthropicContent{Type: "text", Text: m.Content})
+		body.Messages = append(body.Messages, anthropicMessage{Role: m.Role, Content: content})
+	}
+	return body
+}
+
+func (p *anthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResult, error) {
+	var resp anthropicResponse
+	if err := doJSON(ctx, p.client, "POST", p.endpoint("/messages"), p.headers(), p.buildRequest(req), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Content) == 0 {
+		return nil, fmt.Errorf("AI未返回结果")
+	}
+	var text strings.Builder
+	usage := AIUsage{PromptTokens: resp.Usage.InputTokens, CompletionTokens: resp.Usage.OutputTokens}
+	for _, c := range resp.Content {
+		switch c.Type {
+		case "text":
+			text.WriteString(c.Text)
+		case "tool_use":
+			return &ChatResult{Content: string(c.Input), Model: resp.Model, Usage: usage}, nil
+		}
+	}
+	return &ChatResult{Content: text.String(), Model: resp.Model, Usage: usage}, nil
+}
+
+func (p *anthropicProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta func(string, int)) (*ChatResult, error) {
+	body := p.buildRequest(req)
+	body.Stream = true
+
+	res := &ChatResult{}
+	var text, toolInput strings.Builder
+	err := doStream(ctx, aiTimeout(&p.cfg), p.endpoint("/messages"), p.headers(), body, func(line string) error {
+		data, ok := sseData(line)
+		if !ok || data == "" {
+			return nil
+		}
+		var ev anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return fmt.Errorf("解析流式响应失败: %v", err)
+		}
+		switch ev.Type {
+		case "message_start":
+			if ev.Message != nil {
+				res.Model = ev.Message.Model
+				res.Usage.PromptTokens = ev.Message.Usage.InputTokens
+			}
+		case "content_block_delta":
+			if ev.Delta == nil {
+				return nil
+			}
+			// 结构化输出时内容以工具参数（input_json_delta）的形式返回
+			delta := ev.Delta.Text
+			if ev.Delta.Type == "input_json_delta" {
+				delta = ev.Delta.PartialJSON
+				toolInput.WriteString(delta)
+			} else {
+				text.WriteString(delta)
+			}
+			if delta != "" {
+				onDelta(delta, 0)
+			}
+		case "message_delta":
+			if ev.Usage != nil && ev.Usage.OutputTokens > 0 {
+				res.Usage.CompletionTokens = ev.Usage.OutputTokens
+				onDelta("", ev.Usage.OutputTokens)
+			}
+		case "message_stop":
+			return errStreamDone
+		case "error":
+			if ev.Error != nil {
+				return streamError(ev.Error.Type, ev.Error.Message)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Content = text.String()
+	if toolInput.Len() > 0 {
+		res.Content = toolInput.String()
+	}
+	return res, nil
+}
+
+func (p *anthropicProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := doJSON(ctx, p.client, "GET", p.endpoint("/models?limit=1000"), p.headers(), nil, &resp); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(resp.Data))
+	for _, m := range resp.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
+
+func (p *anthropicProvider) Test(ctx context.Context) error {
+	return testChat(ctx, p)
+}
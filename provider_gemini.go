@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ============================================
+// Google Gemini（generateContent）
+// ============================================
+
+const defaultGeminiBaseURL = "https://generativelanguage.googleapis.com"
+
+// geminiProvider Gemini 系列模型
+type geminiProvider struct {
+	cfg    AIConfig
+	client *http.Client
+}
+
+type geminiPart struct {
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+}
+
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"` // user / model
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxOutputTokens  int                    `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiUsage struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+}
+
+// aiUsage 转换为 AIUsage
+func (u *geminiUsage) aiUsage() AIUsage {
+	if u == nil {
+		return AIUsage{}
+	}
+	return AIUsage{PromptTokens: u.PromptTokenCount, CompletionTokens: u.CandidatesTokenCount}
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata *geminiUsage `json:"usageMetadata"`
+	ModelVersion  string       `json:"modelVersion"`
+}
+
+func (p *geminiProvider) Name() string { return ProviderGemini }
+
+// endpoint 接口地址，Base URL 可以带或不带 /v1beta
+func (p *geminiProvider) endpoint(path string) string {
+	base := p.cfg.BaseURL
+	if base == "" {
+		base = defaultGeminiBaseURL
+	}
+	return joinAPIURL(base, "v1beta", path)
+}
+
+func (p *geminiProvider) headers() map[string]string {
+	return map[string]string{"x-goog-api-key": p.cfg.APIKey}
+}
+
+// buildRequest 转换为 generateContent 请求体
+func (p *geminiProvider) buildRequest(req *ChatRequest) geminiRequest {
+	system, messages := splitSystem(req.Messages)
+	body := geminiRequest{GenerationConfig: geminiGenerationConfig{MaxOutputTokens: req.MaxTokens}}
+	if req.Temperature > 0 {
+		body.GenerationConfig.Temperature = &req.Temperature
+	}
+	if f := req.ResponseFormat; f != nil {
+		body.GenerationConfig.ResponseMimeType = "application/json"
+		body.GenerationConfig.ResponseSchema = geminiSchema(f.Schema)
+	}
+	if system != "" {
+		body.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: system}}}
+	}
+	for _, m := range messages {
+		role := "user"
+		if m.Role == "assistant" {
+			role = "model"
+		}
+		parts := []geminiPart{{Text: m.Content}}
+		for _, img := range m.Images {
+			parts = append(parts, geminiPart{InlineData: &geminiInlineData{
+				MimeType: img.MIME, Data: base64.StdEncoding.EncodeToString(img.Data),
+			}})
+		}
+		body.Contents = append(body.Contents, geminiContent{Role: role, Parts: parts})
+	}
+	return body
+}
+
+// modelEndpoint 模型方法的地址，如 generateContent / streamGenerateContent
+func (p *geminiProvider) modelEndpoint(method string) string {
+	model := strings.TrimPrefix(p.cfg.Model, "models/")
+	return p.endpoint("/models/" + url.PathEscape(model) + ":" + method)
+}
+
+func (p *geminiProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResult, error) {
+	var resp geminiResponse
+	if err := doJSON(ctx, p.client, "POST", p.modelEndpoint("generateContent"), p.headers(), p.buildRequest(req), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Candidates) == 0 {
+		if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+			return nil, fmt.Errorf("请求被 Gemini 拦截: %s", resp.PromptFeedback.BlockReason)
+		}
+		return nil, fmt.Errorf("AI未返回结果")
+	}
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return &ChatResult{Content: text.String(), Model: resp.ModelVersion, Usage: resp.UsageMetadata.aiUsage()}, nil
+}
+
+func (p *geminiProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta func(string, int)) (*ChatResult, error) {
+	res := &ChatResult{}
+	var text strings.Builder
+	err := doStream(ctx, aiTimeout(&p.cfg), p.modelEndpoint("streamGenerateContent")+"?alt=sse", p.headers(), p.buildRequest(req), func(line string) error {
+		data, ok := sseData(line)
+		if !ok || data == "" {
+			return nil
+		}
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("解析流式响应失败: %v", err)
+		}
+		if len(chunk.Candidates) == 0 && chunk.PromptFeedback != nil && chunk.PromptFeedback.BlockReason != "" {
+			return fmt.Errorf("请求被 Gemini 拦截: %s", chunk.PromptFeedback.BlockReason)
+		}
+		if chunk.ModelVersion != "" {
+			res.Model = chunk.ModelVersion
+		}
+		var delta strings.Builder
+		for _, c := range chunk.Candidates {
+			for _, part := range c.Content.Parts {
+				delta.WriteString(part.Text)
+			}
+		}
+		tokens := 0
+		if chunk.UsageMetadata != nil {
+			// 每个块的 usageMetadata 是截至当前的累计值
+			res.Usage = chunk.UsageMetadata.aiUsage()
+			tokens = res.Usage.CompletionTokens
+		}
+		text.WriteString(delta.String())
+		onDelta(delta.String(), tokens)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Content = text.String()
+	return res, nil
+}
+
+func (p *geminiProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Models []struct {
+			Name    string   `json:"name"`
+			Methods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := doJSON(ctx, p.client, "GET", p.endpoint("/models?pageSize=1000"), p.headers(), nil, &resp); err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, m := range resp.Models {
+		if containsFold(m.Methods, "generateContent") {
+			models = append(models, strings.TrimPrefix(m.Name, "models/"))
+		}
+	}
+	return models, nil
+}
+
+func (p *geminiProvider) Test(ctx context.Context) error {
+	return testChat(ctx, p)
+}
+
+// geminiSchema 转换为 Gemini 支持的 Schema 子集：类型名大写，不支持 additionalProperties
+func geminiSchema(schema map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		switch k {
+		case "additionalProperties":
+			continue
+		case "type":
+			if t, ok := v.(string); ok {
+				v = strings.ToUpper(t)
+			}
+		case "items":
+			if m, ok := v.(map[string]interface{}); ok {
+				v = geminiSchema(m)
+			}
+		case "properties":
+			if props, ok := v.(map[string]interface{}); ok {
+				converted := make(map[string]interface{}, len(props))
+				for name, p := range props {
+					if m, ok := p.(map[string]interface{}); ok {
+						p = geminiSchema(m)
+					}
+					converted[name] = p
+				}
+				v = converted
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ============================================
+// 本地 Ollama 服务（/api/chat）
+// ============================================
+
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+// ollamaProvider 本地模型，不需要 API Key
+type ollamaProvider struct {
+	cfg    AIConfig
+	client *http.Client
+}
+
+type ollamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"` // base64
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   interface{}     `json:"format,omitempty"` // JSON Schema（Ollama 0.5+）
+	Options  ollamaOptions   `json:"options"`
+}
+
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	EvalCount       int           `json:"eval_count"`        // 输出 token 数，仅最后一条有
+	PromptEvalCount int           `json:"prompt_eval_count"` // 输入 token 数，仅最后一条有
+	Error           string        `json:"error"`
+}
+
+func (p *ollamaProvider) Name() string { return ProviderOllama }
+
+// endpoint 接口地址；Base URL 填成 OpenAI 兼容地址（.../v1）时去掉该后缀
+func (p *ollamaProvider) endpoint(path string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(p.cfg.BaseURL, "/"), "/v1")
+	if base == "" {
+		base = defaultOllamaBaseURL
+	}
+	return joinAPIURL(base, "", path)
+}
+
+// headers 本地服务无需鉴权；经反向代理访问时可以填写 API Key
+func (p *ollamaProvider) headers() map[string]string {
+	if p.cfg.APIKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.cfg.APIKey}
+}
+
+// buildRequest 转换为 /api/chat 请求体
+func (p *ollamaProvider) buildRequest(req *ChatRequest) ollamaRequest {
+	body := ollamaRequest{
+		Model:   p.cfg.Model,
+		Options: ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens},
+	}
+	if req.ResponseFormat != nil {
+		body.Format = req.ResponseFormat.Schema
+	}
+	for _, m := range req.Messages {
+		msg := ollamaMessage{Role: m.Role, Content: m.Content}
+		for _, img := range m.Images {
+			msg.Images = append(msg.Images, base64.StdEncoding.EncodeToString(img.Data))
+		}
+		body.Messages = append(body.Messages, msg)
+	}
+	return body
+}
+
+func (p *ollamaProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResult, error) {
+	var resp ollamaResponse
+	if err := doJSON(ctx, p.client, "POST", p.endpoint("/api/chat"), p.headers(), p.buildRequest(req), &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Done && resp.Message.Content == "" {
+		return nil, fmt.Errorf("AI未返回结果")
+	}
+	return &ChatResult{Content: resp.Message.Content, Model: resp.Model, Usage: AIUsage{PromptTokens: resp.PromptEvalCount, CompletionTokens: resp.EvalCount}}, nil
+}
+
+// ChatStream Ollama 的流式输出是每行一个 JSON（NDJSON），每行通常对应一个 token
+func (p *ollamaProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta func(string, int)) (*ChatResult, error) {
+	body := p.buildRequest(req)
+	body.Stream = true
+
+	res := &ChatResult{}
+	var text strings.Builder
+	chunks := 0
+	err := doStream(ctx, aiTimeout(&p.cfg), p.endpoint("/api/chat"), p.headers(), body, func(line string) error {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		var chunk ollamaResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return fmt.Errorf("解析流式响应失败: %v", err)
+		}
+		if chunk.Error != "" {
+			return streamError("", chunk.Error)
+		}
+		res.Model = chunk.Model
+		if chunk.Message.Content != "" {
+			chunks++
+			text.WriteString(chunk.Message.Content)
+			onDelta(chunk.Message.Content, chunks)
+		}
+		if chunk.Done {
+			res.Usage = AIUsage{PromptTokens: chunk.PromptEvalCount, CompletionTokens: chunk.EvalCount}
+			if chunk.EvalCount > 0 {
+				onDelta("", chunk.EvalCount)
+			}
+			return errStreamDone
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Content = text.String()
+	return res, nil
+}
+
+func (p *ollamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := doJSON(ctx, p.client, "GET", p.endpoint("/api/tags"), p.headers(), nil, &resp); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(resp.Models))
+	for _, m := range resp.Models {
+		models = append(models, m.Name)
+	}
+	return models, nil
+}
+
+func (p *ollamaProvider) Test(ctx context.Context) error {
+	return testChat(ctx, p)
+}
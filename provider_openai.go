@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ============================================
+// OpenAI 兼容接口（/chat/completions）与 Azure OpenAI
+// ============================================
+
+// defaultAzureAPIVersion AIConfig.APIVersion 未设置时使用的 Azure api-version
+const defaultAzureAPIVersion = "2024-06-01"
+
+// azureDeploymentsAPIVersion 列出部署的接口只在旧版 api-version 中提供
+const azureDeploymentsAPIVersion = "2022-12-01"
+
+// openAIProvider OpenAI 及兼容接口（DeepSeek、智谱、Moonshot、硅基流动等）；azure 为 true 时使用 Azure 的部署地址与 api-key 请求头
+type openAIProvider struct {
+	cfg    AIConfig
+	client *http.Client
+	azure  bool
+}
+
+// openAIMessage Content 为字符串，带图片时为 []openAIContentPart
+type openAIMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+}
+
+// openAIContentPart 多模态消息片段
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model,omitempty"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream,omitempty"`
+	StreamOptions  *openAIStreamOptions  `json:"stream_options,omitempty"`
+}
+
+// openAIStreamOptions include_usage 使流式响应在最后一个块返回用量
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIUsage 响应中的 usage
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// aiUsage 转换为 AIUsage
+func (u *openAIUsage) aiUsage() AIUsage {
+	if u == nil {
+		return AIUsage{}
+	}
+	return AIUsage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+// openAIStreamChunk 流式响应中的一个 data 块
+type openAIStreamChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage,omitempty"`
+	Error *struct {
+		Type    string `json:"type"` // server_error / rate_limit_exceeded ...
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type openAIResponseFormat struct {
+	Type       string            `json:"type"` // json_schema / json_object
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+	Strict bool                   `json:"strict"`
+}
+
+func (p *openAIProvider) Name() string {
+	if p.azure {
+		return ProviderAzure
+	}
+	return "openai"
+}
+
+// deployment Azure 部署名，未单独配置时与模型名相同
+func (p *openAIProvider) deployment() string {
+	if p.cfg.Deployment != "" {
+		return p.cfg.Deployment
+	}
+	return p.cfg.Model
+}
+
+// endpoint 接口地址
+func (p *openAIProvider) endpoint(path string) string {
+	if !p.azure {
+		return joinAPIURL(p.cfg.BaseURL, "", path)
+	}
+	version := p.cfg.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+	return joinAPIURL(p.cfg.BaseURL, "", "/openai/deployments/"+url.PathEscape(p.deployment())+path) +
+		"?api-version=" + url.QueryEscape(version)
+}
+
+// headers 鉴权请求头
+func (p *openAIProvider) headers() map[string]string {
+	if p.azure {
+		return map[string]string{"api-key": p.cfg.APIKey}
+	}
+	return map[string]string{"Authorization": "Bearer " + p.cfg.APIKey}
+}
+
+// buildRequest 转换为 /chat/completions 请求体
+func (p *openAIProvider) buildRequest(req *ChatRequest) openAIRequest {
+	body := openAIRequest{
+		Messages:    make([]openAIMessage, 0, len(req.Messages)),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	}
+	if !p.azure {
+		// Azure 的模型由部署决定
+		body.Model = p.cfg.Model
+	}
+	if f := req.ResponseFormat; f != nil {
+		// 只有 OpenAI / Azure 支持 json_schema，其他兼容服务（DeepSeek、智谱等）一般只支持 json_object
+		if p.azure || strings.EqualFold(p.cfg.Provider, "openai") {
+			body.ResponseFormat = &openAIResponseFormat{Type: "json_schema", JSONSchema: &openAIJSONSchema{Name: f.Name, Schema: f.Schema}}
+		} else {
+			body.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+		}
+	}
+	for _, m := range req.Messages {
+		if len(m.Images) == 0 {
+			body.Messages = append(body.Messages, openAIMessage{Role: m.Role, Content: m.Content})
+			continue
+		}
+		parts := []openAIContentPart{{Type: "text", Text: m.Content}}
+		for _, img := range m.Images {
+			parts = append(parts, openAIContentPart{Type: "image_url", ImageURL: &openAIImageURL{
+				URL: "data:" + img.MIME + ";base64," + base64.StdEncoding.EncodeToString(img.Data),
+			}})
+		}
+		body.Messages = append(body.Messages, openAIMessage{Role: m.Role, Content: parts})
+	}
+	return body
+}
+
+func (p *openAIProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResult, error) {
+	var chatResp ChatResponse
+	if err := doJSON(ctx, p.client, "POST", p.endpoint("/chat/completions"), p.headers(), p.buildRequest(req), &chatResp); err != nil {
+		return nil, err
+	}
+	if chatResp.Error != nil {
+		return nil, fmt.Errorf("AI错误: %s", chatResp.Error.Message)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("AI未返回结果")
+	}
+	return &ChatResult{Content: chatResp.Choices[0].Message.Content, Model: chatResp.Model, Usage: chatResp.Usage.aiUsage()}, nil
+}
+
+func (p *openAIProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta func(string, int)) (*ChatResult, error) {
+	body := p.buildRequest(req)
+	body.Stream = true
+	if !p.azure {
+		// Azure 较早的 api-version 不支持 stream_options
+		body.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+
+	res := &ChatResult{}
+	var text strings.Builder
+	chunks := 0 // 兼容接口的每个增量块通常对应一个 token
+	err := doStream(ctx, aiTimeout(&p.cfg), p.endpoint("/chat/completions"), p.headers(), body, func(line string) error {
+		data, ok := sseData(line)
+		if !ok || data == "" {
+			return nil
+		}
+		if data == "[DONE]" {
+			return errStreamDone
+		}
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("解析流式响应失败: %v", err)
+		}
+		if chunk.Error != nil {
+			return streamError(chunk.Error.Type, chunk.Error.Message)
+		}
+		if chunk.Model != "" {
+			res.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			res.Usage = chunk.Usage.aiUsage()
+			onDelta("", res.Usage.CompletionTokens)
+		}
+		for _, c := range chunk.Choices {
+			if c.Delta.Content != "" {
+				chunks++
+				text.WriteString(c.Delta.Content)
+				onDelta(c.Delta.Content, chunks)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Content = text.String()
+	return res, nil
+}
+
+func (p *openAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	u := joinAPIURL(p.cfg.BaseURL, "", "/models")
+	if p.azure {
+		u = joinAPIURL(p.cfg.BaseURL, "", "/openai/deployments?api-version="+azureDeploymentsAPIVersion)
+	}
+	if err := doJSON(ctx, p.client, "GET", u, p.headers(), nil, &resp); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(resp.Data))
+	for _, m := range resp.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
+
+func (p *openAIProvider) Test(ctx context.Context) error {
+	return testChat(ctx, p)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================
+// 流式输出：SSE（OpenAI / Anthropic / Gemini）与 NDJSON（Ollama）
+// ============================================
+
+// StreamingProvider 支持流式输出的服务商；onDelta 收到增量文本，以及服务商报告的累计输出 token 数（未报告时为 0）
+type StreamingProvider interface {
+	ChatStream(ctx context.Context, req *ChatRequest, onDelta func(delta string, outputTokens int)) (*ChatResult, error)
+}
+
+// streamClient 流式请求不设整体超时，由 doStream 按数据间隔判断超时
+var streamClient = &http.Client{}
+
+// errStreamDone onLine 返回该错误表示流已正常结束
+var errStreamDone = errors.New("stream done")
+
+// streamError 流中途返回的错误事件；按错误类型对应到 HTTP 状态码，以便按服务端错误、限流等分类重试
+func streamError(errType, msg string) *apiError {
+	code := http.StatusInternalServerError
+	switch t := strings.ToLower(errType); {
+	case strings.Contains(t, "overloaded"):
+		code = 529
+	case strings.Contains(t, "quota"):
+		code = http.StatusPaymentRequired
+	case strings.Contains(t, "rate_limit"):
+		code = http.StatusTooManyRequests
+	case strings.Contains(t, "authentication"), strings.Contains(t, "api_key"):
+		code = http.StatusUnauthorized
+	case strings.Contains(t, "permission"):
+		code = http.StatusForbidden
+	case strings.Contains(t, "too_large"):
+		code = http.StatusRequestEntityTooLarge
+	case strings.Contains(t, "invalid_request"), strings.Contains(t, "not_found"):
+		code = http.StatusBadRequest
+	}
+	return &apiError{StatusCode: code, Message: msg}
+}
+
+// doStream 发送流式请求并逐行回调；idle 时间内没有收到新数据时中断，持续输出的长回复不会超时
+func doStream(ctx context.Context, idle time.Duration, url string, headers map[string]string, in interface{}, onLine func(line string) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var idled atomic.Bool
+	timer := time.AfterFunc(idle, func() {
+		idled.Store(true)
+		cancel()
+	})
+	defer timer.Stop()
+	idleErr := &streamIdleError{idle: idle}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		if idled.Load() {
+			return idleErr
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return &apiError{StatusCode: resp.StatusCode, Message: apiErrorMessage(body), RetryAfter: retryAfterHeader(resp.Header)}
+	}
+
+	reader := bufio.NewReaderSize(resp.Body, 64<<10)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			timer.Reset(idle)
+			if cbErr := onLine(strings.TrimRight(line, "\r\n")); cbErr != nil {
+				if cbErr == errStreamDone {
+					return nil
+				}
+				return cbErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if idled.Load() {
+				return idleErr
+			}
+			return err
+		}
+	}
+}
+
+// streamIdleError 流式响应长时间没有新数据；实现 Timeout()，按网络超时处理（见 classifyAIError）
+type streamIdleError struct {
+	idle time.Duration
+}
+
+func (e *streamIdleError) Error() string {
+	return fmt.Sprintf("流式响应超过 %s 没有新数据", e.idle)
+}
+
+func (e *streamIdleError) Timeout() bool { return true }
+
+// sseData 取出 SSE 的 data 行内容；其他行（event:、注释、空行）返回 false
+func sseData(line string) (string, bool) {
+	if !strings.HasPrefix(line, "data:") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "data:")), true
+}
+
+// streamChat 流式发送对话请求，req.OnStream 收到累计的部分回复与输出 token 数；
+// 服务商未报告 token 数时按文本估算
+func streamChat(ctx context.Context, sp StreamingProvider, cfg *AIConfig, req *ChatRequest) (*ChatResult, error) {
+	est := tokenEstimator{hanWeight: lookupModelProfile(cfg.Model).hanWeight}
+	var partial strings.Builder
+	estimated := 0
+	return sp.ChatStream(ctx, req, func(delta string, outputTokens int) {
+		partial.WriteString(delta)
+		estimated += est.Count(delta)
+		if outputTokens <= 0 {
+			outputTokens = estimated
+		}
+		req.OnStream(partial.String(), outputTokens)
+	})
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================
+// 服务商限流：每个服务商（类型 + 地址）一组令牌桶，分别限制每分钟请求数与 token 数
+// ============================================
+
+// tokenBucket 令牌桶，容量为每分钟的额度，按秒匀速补充
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // 每秒补充的令牌数
+	last     time.Time
+}
+
+func newTokenBucket(perMinute int) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		last:     time.Now(),
+	}
+}
+
+// reserve 预定 n 个令牌，返回需要等待的时间；令牌可以透支，后来者排在透支部分之后，保证先到先得
+func (b *tokenBucket) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// 单次请求超过整桶容量时按整桶计，否则永远等不到
+	b.tokens -= math.Min(float64(n), b.capacity)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// providerLimiter 一个服务商的请求数 / token 数限制；为 nil 的桶表示不限制
+type providerLimiter struct {
+	rpm, tpm         int
+	requests, tokens *tokenBucket
+}
+
+// limiterKey 限流按服务商计算：同一服务商的不同模型共享额度
+func limiterKey(cfg *AIConfig) string {
+	return providerKind(cfg) + "|" + cfg.BaseURL + "|" + cfg.APIKey
+}
+
+// rateLimiter 返回服务商的限流器，配置变化时重新创建
+func (a *App) rateLimiter(cfg *AIConfig) *providerLimiter {
+	a.limitersMu.Lock()
+	defer a.limitersMu.Unlock()
+	if a.limiters == nil {
+		a.limiters = map[string]*providerLimiter{}
+	}
+	key := limiterKey(cfg)
+	l := a.limiters[key]
+	if l == nil || l.rpm != cfg.RequestsPerMinute || l.tpm != cfg.TokensPerMinute {
+		l = &providerLimiter{rpm: cfg.RequestsPerMinute, tpm: cfg.TokensPerMinute}
+		if l.rpm > 0 {
+			l.requests = newTokenBucket(l.rpm)
+		}
+		if l.tpm > 0 {
+			l.tokens = newTokenBucket(l.tpm)
+		}
+		a.limiters[key] = l
+	}
+	return l
+}
+
+// waitRateLimit 等待直到可以发送一个约 tokens 个 token 的请求
+func (a *App) waitRateLimit(ctx context.Context, cfg *AIConfig, tokens int) error {
+	l := a.rateLimiter(cfg)
+	var wait time.Duration
+	if l.requests != nil {
+		wait = l.requests.reserve(1)
+	}
+	if l.tokens != nil {
+		wait = max(wait, l.tokens.reserve(tokens))
+	}
+	return sleepCtx(ctx, wait)
+}
+
+// sleepCtx 等待 d，ctx 取消时提前返回错误
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// estimateRequestTokens 估算请求占用的 token 数：输入内容加上输出上限（服务商按 max_tokens 计入 TPM）
+func estimateRequestTokens(cfg *AIConfig, req *ChatRequest) int {
+	est := tokenEstimator{hanWeight: lookupModelProfile(cfg.Model).hanWeight}
+	total := req.MaxTokens
+	for _, m := range req.Messages {
+		total += est.Count(m.Content)
+	}
+	return total
+}
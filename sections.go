@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ============================================
+// 简历分节：联系方式 / 个人简介 / 工作经历 / 教育背景 / 技能 / 项目 / 证书
+// ============================================
+
+// 分节类型
+const (
+	SectionContact        = "contact"
+	SectionSummary        = "summary"
+	SectionExperience     = "experience"
+	SectionEducation      = "education"
+	SectionSkills         = "skills"
+	SectionProjects       = "projects"
+	SectionCertifications = "certifications"
+	SectionOther          = "other"
+)
+
+// ResumeSection 简历中的一个分节（按原文顺序保存）
+type ResumeSection struct {
+	Kind    string `json:"kind"`    // 分节类型，见 Section* 常量
+	Heading string `json:"heading"` // 原文中的标题行，开头未命名的部分为空
+	Content string `json:"content"`
+}
+
+// sectionHeadings 各分节的中英文标题关键词（匹配时忽略大小写与空白）
+var sectionHeadings = map[string][]string{
+	SectionContact: {
+		"联系方式", "联系信息", "个人信息", "基本信息", "个人资料", "个人档案",
+		"contact", "contactinformation", "contactinfo", "personalinformation", "personaldetails", "personalinfo",
+	},
+	SectionSummary: {
+		"个人简介", "自我评价", "自我介绍", "个人总结", "个人优势", "职业概述", "个人概述", "求职意向", "职业目标",
+		"summary", "profile", "aboutme", "objective", "careerobjective", "professionalsummary", "careersummary", "personalstatement",
+	},
+	SectionExperience: {
+		"工作经历", "工作经验", "实习经历", "实习经验", "职业经历", "工作履历", "任职经历",
+		"experience", "workexperience", "professionalexperience", "employmenthistory", "workhistory", "employment", "internship", "internships", "careerhistory",
+	},
+	SectionEducation: {
+		"教育背景", "教育经历", "学历背景", "学习经历", "教育",
+		"education", "educationbackground", "academicbackground", "academics",
+	},
+	SectionSkills: {
+		"专业技能", "技能特长", "技能清单", "技术能力", "技术栈", "职业技能", "技能", "个人技能",
+		"skills", "technicalskills", "coreskills", "corecompetencies", "skillset", "techstack", "technologies",
+	},
+	SectionProjects: {
+		"项目经历", "项目经验", "项目", "主要项目", "个人项目",
+		"projects", "projectexperience", "personalprojects", "keyprojects",
+	},
+	SectionCertifications: {
+		"证书", "资格证书", "获奖情况", "获奖经历", "荣誉奖项", "证书与奖项", "荣誉证书", "所获荣誉", "奖项",
+		"certifications", "certificates", "certification", "licenses", "awards", "honors", "honorsandawards", "awardsandcertifications",
+	},
+}
+
+// sectionHeadingIndex 归一化关键词 -> 分节类型
+var sectionHeadingIndex = func() map[string]string {
+	idx := map[string]string{}
+	for kind, kws := range sectionHeadings {
+		for _, kw := range kws {
+			idx[kw] = kind
+		}
+	}
+	return idx
+}()
+
+// segmentResume 按标题行把简历文本切分为分节；第一个标题之前的内容视为联系方式
+func segmentResume(content string) []ResumeSection {
+	var sections []ResumeSection
+	current := &ResumeSection{Kind: SectionContact}
+	var body []string
+
+	flush := func() {
+		current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+		if current.Content != "" || current.Heading != "" {
+			sections = append(sections, *current)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if kind, inline, ok := matchSectionHeading(trimmed); ok {
+			flush()
+			current = &ResumeSection{Kind: kind, Heading: trimmed}
+			if inline != "" {
+				current.Heading = strings.TrimSpace(strings.TrimSuffix(trimmed, inline))
+				body = append(body, inline)
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	// 只有开头一节（没有识别出任何标题）时分节没有意义
+	if len(sections) == 1 && sections[0].Heading == "" {
+		sections[0].Kind = SectionOther
+	}
+	return sections
+}
+
+// matchSectionHeading 判断一行是否为分节标题；"技能：Go, MySQL" 这类行内标题同时返回冒号后的内容
+func matchSectionHeading(line string) (kind string, inline string, ok bool) {
+	if line == "" || utf8.RuneCountInString(line) > 40 {
+		return "", "", false
+	}
+
+	head := line
+	if i := strings.IndexAny(line, ":："); i != -1 {
+		head = line[:i]
+		rest := line[i:]
+		_, size := utf8.DecodeRuneInString(rest)
+		inline = strings.TrimSpace(rest[size:])
+	}
+
+	key := normalizeHeading(head)
+	if key == "" || utf8.RuneCountInString(key) > 30 {
+		return "", "", false
+	}
+	if kind, ok := sectionHeadingIndex[key]; ok {
+		return kind, inline, true
+	}
+	// 中英双语标题，如"工作经历 Work Experience"
+	for kw, kind := range sectionHeadingIndex {
+		if !strings.HasPrefix(key, kw) {
+			continue
+		}
+		if other, ok := sectionHeadingIndex[key[len(kw):]]; ok && other == kind {
+			return kind, inline, true
+		}
+	}
+	return "", "", false
+}
+
+// normalizeHeading 去掉编号、项目符号、括号、空白并转小写，如"一、工 作 经 历" -> "工作经历"
+func normalizeHeading(s string) string {
+	s = strings.TrimLeft(s, "#*•●■◆▪-—=>|【[「『(（ \t")
+	// 去掉中文序号"一、"或数字序号"1." "1、" "01"
+	for _, prefix := range []string{"一", "二", "三", "四", "五", "六", "七", "八", "九", "十"} {
+		if rest := strings.TrimPrefix(s, prefix); rest != s && strings.HasPrefix(rest, "、") {
+			s = strings.TrimPrefix(rest, "、")
+			break
+		}
+	}
+	s = strings.TrimLeftFunc(s, unicode.IsDigit)
+	s = strings.TrimLeft(s, ".、)） \t")
+
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+		case strings.ContainsRune("】]」』)）&/|·•-_", r):
+		case r == '＆':
+		default:
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return strings.TrimFunc(sb.String(), func(r rune) bool { return !unicode.IsLetter(r) })
+}
+
+// sectionPriority 生成提示词时保留分节的优先级（越靠前越重要）
+var sectionPriority = []string{
+	SectionContact, SectionExperience, SectionSkills, SectionProjects,
+	SectionEducation, SectionSummary, SectionCertifications, SectionOther,
+}
+
+// prioritizeSections 内容过长时按优先级与岗位相关度挑选分节，保留原文顺序输出，返回文本与被省略的分节标题
+func prioritizeSections(sections []ResumeSection, keywords []string, maxTokens int, est tokenEstimator) (string, []string) {
+	if len(sections) == 0 {
+		return "", nil
+	}
+	budget := maxTokens
+	included := make([]string, len(sections))
+	// 第一轮按顺序放入能完整容纳的分节；放不下的先跳过，避免一个超长分节挤掉后面篇幅很小的技能、教育等分节
+	var skipped []int
+	for _, i := range sectionOrder(sections, keywords) {
+		text := sectionText(sections[i])
+		if cost := est.Count(text); cost <= budget {
+			included[i] = text
+			budget -= cost + 1
+			continue
+		}
+		skipped = append(skipped, i)
+	}
+	// 第二轮用剩余预算截取最重要的未放入分节的开头部分
+	var dropped []string
+	for _, i := range skipped {
+		if budget > sectionTruncateMinimum {
+			included[i] = est.Truncate(sectionText(sections[i]), budget) + "\n...(本节已截断)"
+			budget = 0
+			continue
+		}
+		dropped = append(dropped, sectionLabel(sections[i]))
+	}
+
+	var parts []string
+	for _, text := range included {
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n"), dropped
+}
+
+// sectionText 分节标题与内容拼接为一段文本
+func sectionText(sec ResumeSection) string {
+	if sec.Heading == "" {
+		return sec.Content
+	}
+	return sec.Heading + "\n" + sec.Content
+}
+
+// sectionLabel 分节在提示中的显示名
+func sectionLabel(sec ResumeSection) string {
+	if sec.Heading != "" {
+		return sec.Heading
+	}
+	return fmt.Sprintf("[%s]", sec.Kind)
+}
+
+// truncateRunes 按字节上限截取，但不会切断多字节字符
+func truncateRunes(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// resumeSections 为新提取的内容分节；占位内容不分节
+func resumeSections(content string, report *ExtractionReport) []ResumeSection {
+	if report != nil && report.UsedPlaceholder {
+		return nil
+	}
+	return segmentResume(content)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ============================================
+// 用量与费用：记录每次请求的 token 用量，按价格表计算费用，控制项目预算
+// ============================================
+
+// analysisCompletionEstimate 没有历史数据时，估算单次分析费用使用的输出 token 数
+const analysisCompletionEstimate = 1500
+
+// AIUsage token 用量与费用
+type AIUsage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Calls            int     `json:"calls,omitempty"`     // 请求次数（含结构化输出的修正请求）
+	Estimated        bool    `json:"estimated,omitempty"` // 部分请求的服务商未返回用量，按文本估算
+	Cost             float64 `json:"cost"`                // 费用（美元），价格表中没有的模型计为 0
+}
+
+// ModelPrice 模型价格（美元 / 百万 token），按模型名前缀匹配
+type ModelPrice struct {
+	Model  string  `json:"model"`
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// defaultModelPrices 常见模型的公开价格；Config.Pricing 中的同名前缀优先
+var defaultModelPrices = []ModelPrice{
+	{"gpt-5", 1.25, 10},
+	{"gpt-5-mini", 0.25, 2},
+	{"gpt-5-nano", 0.05, 0.4},
+	{"gpt-4o", 2.5, 10},
+	{"gpt-4o-mini", 0.15, 0.6},
+	{"gpt-4.1", 2, 8},
+	{"gpt-4.1-mini", 0.4, 1.6},
+	{"gpt-4.1-nano", 0.1, 0.4},
+	{"gpt-4-turbo", 10, 30},
+	{"gpt-3.5-turbo", 0.5, 1.5},
+	{"o3-mini", 1.1, 4.4},
+	{"o4-mini", 1.1, 4.4},
+	{"claude-3-5-sonnet", 3, 15},
+	{"claude-3-7-sonnet", 3, 15},
+	{"claude-sonnet-4", 3, 15},
+	{"claude-3-5-haiku", 0.8, 4},
+	{"claude-3-haiku", 0.25, 1.25},
+	{"claude-opus-4", 15, 75},
+	{"gemini-1.5-flash", 0.075, 0.3},
+	{"gemini-1.5-pro", 1.25, 5},
+	{"gemini-2.0-flash", 0.1, 0.4},
+	{"gemini-2.5-flash", 0.3, 2.5},
+	{"gemini-2.5-pro", 1.25, 10},
+	{"deepseek-chat", 0.27, 1.1},
+	{"deepseek-reasoner", 0.55, 2.19},
+	{"deepseek-v3", 0.28, 0.42}, // 硅基流动等平台的 deepseek-ai/DeepSeek-V3.x
+	{"deepseek-r1", 0.55, 2.19},
+	{"glm-4", 0.6, 2.2},
+	{"glm-4.7-flash", 0, 0},
+	{"kimi-k2", 0.6, 2.5},
+	{"kimi-k2.5", 0.6, 3},
+	{"moonshot-v1-32k", 3.3, 3.3},
+	{"moonshot-v1-128k", 8.4, 8.4},
+	{"qwen2.5-72b", 0.57, 0.57},
+}
+
+// add 累加另一次请求的用量
+func (u *AIUsage) add(o AIUsage) {
+	u.PromptTokens += o.PromptTokens
+	u.CompletionTokens += o.CompletionTokens
+	u.Calls += o.Calls
+	u.Estimated = u.Estimated || o.Estimated
+	u.Cost += o.Cost
+}
+
+// normalizeModelName 小写并去掉组织前缀（"openai/gpt-4o"、"deepseek-ai/DeepSeek-V3"）
+func normalizeModelName(model string) string {
+	name := strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// matchModelPrice 在价格表中查找最长前缀匹配
+func matchModelPrice(prices []ModelPrice, name string) (ModelPrice, bool) {
+	var best ModelPrice
+	found := false
+	for _, p := range prices {
+		prefix := normalizeModelName(p.Model)
+		if prefix != "" && strings.HasPrefix(name, prefix) && len(prefix) > len(best.Model) {
+			best, found = ModelPrice{Model: prefix, Input: p.Input, Output: p.Output}, true
+		}
+	}
+	return best, found
+}
+
+// modelPrice 模型价格：先查配置的价格表，再查内置价格
+func (a *App) modelPrice(model string) (ModelPrice, bool) {
+	name := normalizeModelName(model)
+	if p, ok := matchModelPrice(a.currentConfig().Pricing, name); ok {
+		return p, true
+	}
+	return matchModelPrice(defaultModelPrices, name)
+}
+
+// usageCost 按模型价格计算费用
+func (a *App) usageCost(model string, promptTokens, completionTokens int) float64 {
+	price, ok := a.modelPrice(model)
+	if !ok {
+		return 0
+	}
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6
+}
+
+// estimateUsage 服务商未返回用量时按请求与回复文本估算
+func estimateUsage(cfg *AIConfig, req *ChatRequest, content string) AIUsage {
+	est := tokenEstimator{hanWeight: lookupModelProfile(cfg.Model).hanWeight}
+	prompt := 0
+	for _, m := range req.Messages {
+		prompt += est.Count(m.Content)
+	}
+	return AIUsage{PromptTokens: prompt, CompletionTokens: est.Count(content), Estimated: true}
+}
+
+// recordUsage 把一次请求的用量计入 total，按实际使用的模型计算费用
+func (a *App) recordUsage(total *AIUsage, cfg *AIConfig, res *ChatResult) {
+	u := res.Usage
+	u.Calls = 1
+	u.Cost = a.usageCost(cfg.Model, u.PromptTokens, u.CompletionTokens)
+	total.add(u)
+}
+
+// chargeResume 把一次分析的用量计入简历的累计花费，由调用方保存简历
+func (a *App) chargeResume(resume *Resume, usage *AIUsage) {
+	if usage.Calls == 0 {
+		return
+	}
+	if resume.Spend == nil {
+		resume.Spend = &AIUsage{}
+	}
+	resume.Spend.add(*usage)
+}
+
+// resumeSpend 简历的累计花费
+func (a *App) resumeSpend(id string) float64 {
+	if r := a.loadResume(id); r != nil && r.Spend != nil {
+		return r.Spend.Cost
+	}
+	return 0
+}
+
+// projectSpend 项目已花费的费用（各简历累计花费之和）与产生过费用的简历数
+func (a *App) projectSpend(projectID string) (float64, int) {
+	spent, n := 0.0, 0
+	for _, r := range a.GetProjectResumes(projectID) {
+		if r.Spend != nil && r.Spend.Calls > 0 {
+			spent += r.Spend.Cost
+			n++
+		}
+	}
+	return spent, n
+}
+
+// budgetGuard 项目预算：开始分析一份简历前按预计费用预留额度，结束后按实际费用结算
+type budgetGuard struct {
+	mu       sync.Mutex
+	limit    float64
+	spent    float64 // 已结算的费用（含任务开始前的花费）
+	reserved float64 // 进行中的简历预留的额度
+	estimate float64 // 单份简历的预计费用，随实际费用更新为平均值
+	settled  int     // 本次任务已结算的份数
+	total    float64 // 本次任务已结算的费用
+	exceeded bool
+}
+
+// newBudgetGuard 创建项目预算；项目未设置预算或模型价格未知时返回 nil（不限制）
+func (a *App) newBudgetGuard(p *Project, cfg *AIConfig, pending []*Resume) (*budgetGuard, error) {
+	if p.BudgetLimit <= 0 {
+		return nil, nil
+	}
+	// 无法计价时不能按预算限制，拒绝开始而不是静默忽略预算
+	if _, ok := a.modelPrice(cfg.Model); !ok {
+		return nil, fmt.Errorf("模型 %s 不在价格表中，无法按预算上限 $%.2f 控制花费，请在配置的价格表（pricing）中添加该模型或取消项目预算", cfg.Model, p.BudgetLimit)
+	}
+	spent, charged := a.projectSpend(p.ID)
+	if spent >= p.BudgetLimit {
+		return nil, fmt.Errorf("项目已花费 $%.4f，达到预算上限 $%.2f", spent, p.BudgetLimit)
+	}
+
+	estimate := 0.0
+	if charged > 0 {
+		estimate = spent / float64(charged)
+	} else if len(pending) > 0 {
+		// 没有历史数据时按待分析简历的平均篇幅估算，并计入提示词中岗位要求等固定部分
+		est := tokenEstimator{hanWeight: lookupModelProfile(cfg.Model).hanWeight}
+		tokens := 0
+		for _, r := range pending {
+			tokens += min(est.Count(r.Content), maxResumePromptTokens)
+		}
+		estimate = a.usageCost(cfg.Model, tokens/len(pending)+promptSafetyMargin*2, analysisCompletionEstimate)
+	}
+	if estimate <= 0 {
+		// 免费模型不产生花费
+		return nil, nil
+	}
+	return &budgetGuard{limit: p.BudgetLimit, spent: spent, estimate: estimate}, nil
+}
+
+// SetProjectBudget 设置项目的分析费用上限（美元），0 表示不限制
+func (a *App) SetProjectBudget(projectID string, limit float64) error {
+	if limit < 0 {
+		return fmt.Errorf("预算不能为负数")
+	}
+	if a.modifyProject(projectID, func(p *Project) { p.BudgetLimit = limit }) == nil {
+		return fmt.Errorf("项目不存在: %s", projectID)
+	}
+	return nil
+}
+
+// reserve 预留一份简历的额度，返回预留的金额，超出预算时返回 false；nil 表示不限制
+func (b *budgetGuard) reserve() (float64, bool) {
+	if b == nil {
+		return 0, true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// 一旦判定超出就不再开始新的简历，即使之后结算的实际费用低于预计
+	if b.exceeded || b.spent+b.reserved+b.estimate > b.limit {
+		b.exceeded = true
+		return 0, false
+	}
+	b.reserved += b.estimate
+	return b.estimate, true
+}
+
+// settle 结算一份简历的实际费用，释放 reserve 预留的额度
+func (b *budgetGuard) settle(reserved, cost float64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reserved -= reserved
+	b.spent += cost
+	if cost > 0 {
+		b.settled++
+		b.total += cost
+		b.estimate = b.total / float64(b.settled)
+	}
+}
+
+// stopReason 因预算停止时的原因，未超出时为空
+func (b *budgetGuard) stopReason() string {
+	if b == nil {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.exceeded {
+		return ""
+	}
+	return fmt.Sprintf("已达到项目预算上限 $%.2f（已花费 $%.4f）", b.limit, b.spent)
+}